@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+
 	"github.com/aternity/zense/config"
 	"github.com/sirupsen/logrus"
 )
@@ -28,12 +30,60 @@ func main() {
 		logrus.Panic(err.Error())
 	}
 
+	llm, err := config.NewLLM(cfg.LLM).Client(context.Background())
+	if err != nil {
+		logrus.Panic(err.Error())
+	}
+
+	classifier, err := config.NewSafety(cfg.Safety).Classifier()
+	if err != nil {
+		logrus.Panic(err.Error())
+	}
+
+	prompts, err := config.NewPrompt(cfg.Prompt).Registry()
+	if err != nil {
+		logrus.Panic(err.Error())
+	}
+
+	jwt, err := config.NewJWT(cfg.JWT).Signer()
+	if err != nil {
+		logrus.Panic(err.Error())
+	}
+
+	mailer, err := config.NewMail(cfg.Mail).Mailer()
+	if err != nil {
+		logrus.Panic(err.Error())
+	}
+
+	lockout, err := config.NewLockout(cfg.Lockout).Tracker(db)
+	if err != nil {
+		logrus.Panic(err.Error())
+	}
+
+	providers, err := config.NewOIDC(cfg.OIDC).Registry()
+	if err != nil {
+		logrus.Panic(err.Error())
+	}
+
 	if err := config.NewServer(config.Server{
-		Host:  cfg.Server.Host,
-		Port:  cfg.Server.Port,
-		Genai: cfg.Server.Genai,
-		DB:    db,
-		JWT:   cfg.Server.JWT,
+		Host:       cfg.Server.Host,
+		Port:       cfg.Server.Port,
+		LLM:        llm,
+		Safety:     classifier,
+		Prompts:    prompts,
+		Mailer:     mailer,
+		Lockout:    lockout,
+		OIDC:       providers,
+		DB:         db,
+		JWT:        jwt,
+		Auth:       cfg.Server.Auth,
+		TwoFactor:  cfg.Server.TwoFactor,
+		Pseudonyms: cfg.Server.Pseudonyms,
+		Admin:      cfg.Server.Admin,
+		Vent:       cfg.Server.Vent,
+		Comment:    cfg.Server.Comment,
+		Account:    cfg.Server.Account,
+		Retention:  cfg.Server.Retention,
 	}).Run(); err != nil {
 		logrus.Panic(err.Error())
 	}
@@ -1,15 +1,34 @@
 package config
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/aternity/zense/internal/oidc"
+	"github.com/aternity/zense/internal/service"
 	"github.com/aternity/zense/internal/util"
 	"github.com/joho/godotenv"
 )
 
 type App struct {
 	Server   Server
+	JWT      JWT
 	Database Database
+	LLM      LLM
+	Safety   Safety
+	Prompt   Prompt
+	Mail     Mail
+	Lockout  Lockout
+	OIDC     OIDC
+}
+
+type Admin struct {
+	Email    string
+	Password string
 }
 
 func New() (*App, error) {
@@ -17,14 +36,63 @@ func New() (*App, error) {
 		return nil, err
 	}
 
+	appURL := getString("APP_URL", "http://localhost:3000")
+	reauthWindow := getDuration("ACCOUNT_REAUTH_WINDOW", 10*time.Minute)
+
+	pseudonymSecret := os.Getenv("PSEUDONYM_SECRET")
+	if pseudonymSecret == "" {
+		return nil, errors.New("set PSEUDONYM_SECRET, anonymous posts cannot be named without it")
+	}
+
 	return &App{
 		Server: Server{
-			Host:  os.Getenv("APP_HOST"),
-			Port:  os.Getenv("APP_PORT"),
-			Genai: os.Getenv("GEMINI_API_KEY"),
-			JWT: util.JWT{
-				Secret: os.Getenv("JWT_SECRET"),
+			Host: os.Getenv("APP_HOST"),
+			Port: os.Getenv("APP_PORT"),
+			Auth: service.AuthConfig{
+				RefreshTTL:     getDuration("JWT_REFRESH_TTL", 30*24*time.Hour),
+				WorkerInterval: getDuration("AUTH_WORKER_INTERVAL", time.Hour),
+				VerifyTTL:      getDuration("EMAIL_VERIFY_TTL", 48*time.Hour),
+				ResetTTL:       getDuration("PASSWORD_RESET_TTL", time.Hour),
+				ChallengeTTL:   getDuration("TWO_FACTOR_CHALLENGE_TTL", 5*time.Minute),
+				ReauthWindow:   reauthWindow,
+				AppURL:         appURL,
+			},
+			TwoFactor: service.TwoFactorConfig{
+				Issuer: getString("TOTP_ISSUER", "Zense"),
+			},
+			Pseudonyms: util.Pseudonyms{
+				Secret: pseudonymSecret,
+			},
+			Admin: Admin{
+				Email:    os.Getenv("ADMIN_EMAIL"),
+				Password: os.Getenv("ADMIN_PASSWORD"),
+			},
+			Vent: service.VentConfig{
+				SessionTTL:  getDuration("VENT_SESSION_TTL", 30*time.Minute),
+				MaxMessages: getInt("VENT_SESSION_MAX_MESSAGES", 50),
+				TokenBudget: getInt("VENT_TOKEN_BUDGET", 2000),
+			},
+			Comment: service.CommentConfig{
+				MaxDepth: getInt("COMMENT_MAX_DEPTH", 5),
 			},
+			Account: service.AccountConfig{
+				ExportDir:      getString("ACCOUNT_EXPORT_DIR", filepath.Join(os.TempDir(), "zense-exports")),
+				ExportTTL:      getDuration("ACCOUNT_EXPORT_TTL", 7*24*time.Hour),
+				DeletionGrace:  getDuration("ACCOUNT_DELETION_GRACE", 30*24*time.Hour),
+				WorkerInterval: getDuration("ACCOUNT_WORKER_INTERVAL", time.Minute),
+				ReauthWindow:   reauthWindow,
+			},
+			Retention: service.RetentionConfig{
+				Retention:      getDuration("CONTENT_RETENTION", 30*24*time.Hour),
+				WorkerInterval: getDuration("CONTENT_PURGE_INTERVAL", time.Hour),
+			},
+		},
+		JWT: JWT{
+			Secret:   os.Getenv("JWT_SECRET"),
+			KeyFiles: getList("JWT_KEY_FILES"),
+			Issuer:   getString("JWT_ISSUER", appURL),
+			Audience: getString("JWT_AUDIENCE", "zense-api"),
+			TTL:      getDuration("JWT_ACCESS_TTL", 15*time.Minute),
 		},
 		Database: Database{
 			Host: os.Getenv("DB_HOST"),
@@ -33,5 +101,109 @@ func New() (*App, error) {
 			Name: os.Getenv("DB_NAME"),
 			Port: os.Getenv("DB_PORT"),
 		},
+		LLM: LLM{
+			Provider: os.Getenv("LLM_PROVIDER"),
+			Model:    os.Getenv("LLM_MODEL"),
+			APIKey:   llmAPIKey(),
+			BaseURL:  os.Getenv("LLM_BASE_URL"),
+		},
+		Safety: Safety{
+			RulesFile: os.Getenv("SAFETY_RULES_FILE"),
+		},
+		Prompt: Prompt{
+			Dir: os.Getenv("PROMPT_TEMPLATE_DIR"),
+		},
+		Mail: Mail{
+			Driver:     os.Getenv("MAIL_DRIVER"),
+			Host:       os.Getenv("SMTP_HOST"),
+			Port:       getString("SMTP_PORT", "587"),
+			Username:   os.Getenv("SMTP_USERNAME"),
+			Password:   os.Getenv("SMTP_PASSWORD"),
+			RequireTLS: getBool("SMTP_REQUIRE_TLS", os.Getenv("SMTP_USERNAME") != ""),
+			From:       getString("MAIL_FROM", "Zense <no-reply@zense.local>"),
+			Dir:        getString("MAIL_DIR", filepath.Join(os.TempDir(), "zense-mail")),
+		},
+		Lockout: Lockout{
+			Store:            os.Getenv("LOCKOUT_STORE"),
+			AccountThreshold: getInt("LOCKOUT_ACCOUNT_THRESHOLD", 5),
+			IPThreshold:      getInt("LOCKOUT_IP_THRESHOLD", 20),
+			BaseDelay:        getDuration("LOCKOUT_BASE_DELAY", time.Minute),
+			MaxDelay:         getDuration("LOCKOUT_MAX_DELAY", time.Hour),
+			Window:           getDuration("LOCKOUT_WINDOW", time.Hour),
+		},
+		OIDC: OIDC{
+			Providers: oidcProviders(getString("OIDC_REDIRECT_URL", appURL+"/oidc/callback")),
+		},
 	}, nil
 }
+
+// llmAPIKey keeps GEMINI_API_KEY working for existing deployments.
+func llmAPIKey() string {
+	if key := os.Getenv("LLM_API_KEY"); key != "" {
+		return key
+	}
+	return os.Getenv("GEMINI_API_KEY")
+}
+
+// oidcProviders reads the providers listed in OIDC_PROVIDERS, each
+// configured with OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID,
+// OIDC_<NAME>_CLIENT_SECRET and optionally OIDC_<NAME>_SCOPES.
+func oidcProviders(redirectURL string) []oidc.Config {
+	var providers []oidc.Config
+	for _, name := range getList("OIDC_PROVIDERS") {
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, oidc.Config{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(os.Getenv(prefix + "SCOPES")),
+			RedirectURL:  redirectURL,
+		})
+	}
+	return providers
+}
+
+func getString(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getList splits a comma separated value, leaving out empty items.
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getDuration ignores values that are not positive, none of the durations
+// can be zero and the worker intervals would panic the tickers.
+func getDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aternity/zense/internal/util"
+)
+
+// minSecretLength is the shortest JWT_SECRET accepted for HS256, the size of
+// its SHA-256 key.
+const minSecretLength = 32
+
+type JWT struct {
+	Secret   string
+	KeyFiles []string
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+}
+
+func NewJWT(j JWT) *JWT {
+	return &JWT{
+		Secret:   j.Secret,
+		KeyFiles: j.KeyFiles,
+		Issuer:   j.Issuer,
+		Audience: j.Audience,
+		TTL:      j.TTL,
+	}
+}
+
+// Signer loads the key files, the first one signs and the others only verify.
+// Without key files tokens are signed with HS256 and the secret.
+func (j *JWT) Signer() (*util.JWT, error) {
+	if len(j.KeyFiles) == 0 {
+		if len(j.Secret) < minSecretLength {
+			return nil, errors.New("set JWT_KEY_FILES, or a JWT_SECRET of at least 32 bytes")
+		}
+		return util.NewJWT(j.Issuer, j.Audience, j.TTL, util.NewHMACKey(j.Secret))
+	}
+
+	keys := make([]*util.Key, 0, len(j.KeyFiles))
+	for _, file := range j.KeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		key, err := util.ParseKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		keys = append(keys, key)
+	}
+
+	return util.NewJWT(j.Issuer, j.Audience, j.TTL, keys...)
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aternity/zense/internal/llm"
+)
+
+type LLM struct {
+	Provider string
+	Model    string
+	APIKey   string
+	BaseURL  string
+}
+
+func NewLLM(l LLM) *LLM {
+	return &LLM{
+		Provider: l.Provider,
+		Model:    l.Model,
+		APIKey:   l.APIKey,
+		BaseURL:  l.BaseURL,
+	}
+}
+
+func (l *LLM) Client(ctx context.Context) (llm.Provider, error) {
+	switch l.Provider {
+	case "", "gemini":
+		model := l.Model
+		if model == "" {
+			model = "gemini-1.5-flash"
+		}
+		return llm.NewGemini(ctx, l.APIKey, model)
+	case "openai":
+		return llm.NewOpenAI(l.BaseURL, l.APIKey, l.Model), nil
+	case "stub":
+		return llm.NewStub(), nil
+	default:
+		return nil, fmt.Errorf("unknown llm provider %q", l.Provider)
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aternity/zense/internal/lockout"
+	"gorm.io/gorm"
+)
+
+type Lockout struct {
+	Store            string
+	AccountThreshold int
+	IPThreshold      int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	Window           time.Duration
+}
+
+func NewLockout(l Lockout) *Lockout {
+	return &Lockout{
+		Store:            l.Store,
+		AccountThreshold: l.AccountThreshold,
+		IPThreshold:      l.IPThreshold,
+		BaseDelay:        l.BaseDelay,
+		MaxDelay:         l.MaxDelay,
+		Window:           l.Window,
+	}
+}
+
+func (l *Lockout) Tracker(db *gorm.DB) (*lockout.Tracker, error) {
+	var store lockout.Store
+	switch l.Store {
+	case "", "postgres":
+		s, err := lockout.NewPostgres(db)
+		if err != nil {
+			return nil, err
+		}
+		store = s
+	case "memory":
+		store = lockout.NewMemory()
+	default:
+		return nil, fmt.Errorf("unknown lockout store %q", l.Store)
+	}
+
+	return lockout.NewTracker(store, lockout.Policy{
+		AccountThreshold: l.AccountThreshold,
+		IPThreshold:      l.IPThreshold,
+		BaseDelay:        l.BaseDelay,
+		MaxDelay:         l.MaxDelay,
+		Window:           l.Window,
+	}), nil
+}
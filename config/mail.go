@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/aternity/zense/internal/mail"
+)
+
+type Mail struct {
+	Driver   string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	// RequireTLS refuses to send mail through SMTP servers that do not offer
+	// STARTTLS.
+	RequireTLS bool
+	From       string
+	Dir        string
+}
+
+func NewMail(m Mail) *Mail {
+	return &Mail{
+		Driver:     m.Driver,
+		Host:       m.Host,
+		Port:       m.Port,
+		Username:   m.Username,
+		Password:   m.Password,
+		RequireTLS: m.RequireTLS,
+		From:       m.From,
+		Dir:        m.Dir,
+	}
+}
+
+func (m *Mail) Mailer() (mail.Mailer, error) {
+	switch m.Driver {
+	case "", "log":
+		return mail.NewLog(), nil
+	case "file":
+		return mail.NewFile(m.Dir, m.From), nil
+	case "smtp":
+		return mail.NewSMTP(m.Host, m.Port, m.Username, m.Password, m.From, m.RequireTLS), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", m.Driver)
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/aternity/zense/internal/oidc"
+)
+
+type OIDC struct {
+	Providers []oidc.Config
+}
+
+func NewOIDC(o OIDC) *OIDC {
+	return &OIDC{
+		Providers: o.Providers,
+	}
+}
+
+func (o *OIDC) Registry() (oidc.Providers, error) {
+	for _, provider := range o.Providers {
+		if provider.Issuer == "" || provider.ClientID == "" {
+			return nil, fmt.Errorf("oidc provider %q needs an issuer and a client ID", provider.Name)
+		}
+	}
+
+	return oidc.NewProviders(o.Providers), nil
+}
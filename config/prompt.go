@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+
+	"github.com/aternity/zense/internal/prompt"
+)
+
+type Prompt struct {
+	Dir string
+}
+
+func NewPrompt(p Prompt) *Prompt {
+	return &Prompt{
+		Dir: p.Dir,
+	}
+}
+
+// Registry loads the built-in templates, then the ones in Dir so they can
+// override or add versions without a rebuild.
+func (p *Prompt) Registry() (*prompt.Registry, error) {
+	registry := prompt.NewRegistry()
+	if err := registry.LoadDefaults(); err != nil {
+		return nil, err
+	}
+
+	if p.Dir != "" {
+		if err := registry.LoadFS(os.DirFS(p.Dir)); err != nil {
+			return nil, err
+		}
+	}
+
+	return registry, nil
+}
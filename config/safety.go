@@ -0,0 +1,28 @@
+package config
+
+import (
+	"github.com/aternity/zense/internal/safety"
+)
+
+type Safety struct {
+	RulesFile string
+}
+
+func NewSafety(s Safety) *Safety {
+	return &Safety{
+		RulesFile: s.RulesFile,
+	}
+}
+
+func (s *Safety) Classifier() (safety.Classifier, error) {
+	rules := safety.DefaultRules()
+	if s.RulesFile != "" {
+		var err error
+		rules, err = safety.LoadRules(s.RulesFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return safety.NewRuleClassifier(rules, "id"), nil
+}
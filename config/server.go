@@ -5,81 +5,180 @@ import (
 	"net/http"
 
 	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
 	"github.com/aternity/zense/internal/handler"
 	https "github.com/aternity/zense/internal/http"
+	"github.com/aternity/zense/internal/llm"
+	"github.com/aternity/zense/internal/lockout"
+	"github.com/aternity/zense/internal/mail"
+	"github.com/aternity/zense/internal/migration"
+	"github.com/aternity/zense/internal/oidc"
+	"github.com/aternity/zense/internal/prompt"
 	"github.com/aternity/zense/internal/repository"
+	"github.com/aternity/zense/internal/safety"
 	"github.com/aternity/zense/internal/service"
 	"github.com/aternity/zense/internal/util"
 	"github.com/go-playground/validator/v10"
-	"github.com/google/generative-ai-go/genai"
 	"github.com/labstack/echo/v4"
-	"google.golang.org/api/option"
 	"gorm.io/gorm"
 )
 
 type Server struct {
-	Host  string
-	Port  string
-	Genai string
-	DB    *gorm.DB
-	JWT   util.JWT
+	Host       string
+	Port       string
+	LLM        llm.Provider
+	Safety     safety.Classifier
+	Prompts    *prompt.Registry
+	Mailer     mail.Mailer
+	Lockout    *lockout.Tracker
+	OIDC       oidc.Providers
+	DB         *gorm.DB
+	JWT        *util.JWT
+	Auth       service.AuthConfig
+	TwoFactor  service.TwoFactorConfig
+	Pseudonyms util.Pseudonyms
+	Admin      Admin
+	Vent       service.VentConfig
+	Comment    service.CommentConfig
+	Account    service.AccountConfig
+	Retention  service.RetentionConfig
 }
 
 func NewServer(server Server) *Server {
 	return &Server{
-		Host:  server.Host,
-		Port:  server.Port,
-		Genai: server.Genai,
-		DB:    server.DB,
-		JWT:   server.JWT,
+		Host:       server.Host,
+		Port:       server.Port,
+		LLM:        server.LLM,
+		Safety:     server.Safety,
+		Prompts:    server.Prompts,
+		Mailer:     server.Mailer,
+		Lockout:    server.Lockout,
+		OIDC:       server.OIDC,
+		DB:         server.DB,
+		JWT:        server.JWT,
+		Auth:       server.Auth,
+		TwoFactor:  server.TwoFactor,
+		Pseudonyms: server.Pseudonyms,
+		Admin:      server.Admin,
+		Vent:       server.Vent,
+		Comment:    server.Comment,
+		Account:    server.Account,
+		Retention:  server.Retention,
 	}
 }
 
 func (s *Server) Run() error {
 	e := echo.New()
+	// Only trust X-Forwarded-For from proxies on private networks, so
+	// clients cannot pick the IP address login attempts are counted for.
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
 
-	ctx := context.Background()
-	client, err := genai.NewClient(ctx, option.WithAPIKey(s.Genai))
-	if err != nil {
-		return err
-	}
-
-	jwt := util.NewJWT(s.JWT.Secret)
+	pseudonyms := util.NewPseudonyms(s.Pseudonyms.Secret)
 	validator := validator.New(validator.WithRequiredStructEnabled())
 
-	ventService := service.NewVentService(client)
+	safetyEventRepository := repository.NewSafetyEventRepository(s.DB)
+	ventReplyRepository := repository.NewVentReplyRepository(s.DB)
+	promptTemplateRepository := repository.NewPromptTemplateRepository(s.DB)
+	ventService := service.NewVentService(s.LLM, s.Safety, s.Prompts, safetyEventRepository, ventReplyRepository, s.Vent)
 	ventHandler := handler.NewVentHandler(ventService, validator)
 
 	userRepository := repository.NewUserRepository(s.DB)
-	userService := service.NewUserService(userRepository, jwt)
+	sessionRepository := repository.NewSessionRepository(s.DB)
+	userTokenRepository := repository.NewUserTokenRepository(s.DB)
+	twoFactorRepository := repository.NewTwoFactorRepository(s.DB)
+	userService := service.NewUserService(userRepository, sessionRepository, userTokenRepository, twoFactorRepository, s.Mailer, s.Auth)
 	userHandler := handler.NewUserHandler(userService, validator)
 
+	identityRepository := repository.NewIdentityRepository(s.DB)
+	authService := service.NewAuthService(userRepository, sessionRepository, userTokenRepository, twoFactorRepository, identityRepository, s.OIDC, s.Mailer, s.Lockout, s.JWT, s.Auth)
+	authHandler := handler.NewAuthHandler(authService, validator)
+
+	twoFactorService := service.NewTwoFactorService(userRepository, twoFactorRepository, s.TwoFactor)
+	twoFactorHandler := handler.NewTwoFactorHandler(twoFactorService, validator)
+
+	identityService := service.NewIdentityService(identityRepository, userRepository, s.OIDC)
+	identityHandler := handler.NewIdentityHandler(identityService, validator)
+
+	reactionRepository := repository.NewReactionRepository(s.DB)
+
 	journalRepository := repository.NewJournalRepository(s.DB)
-	journalService := service.NewJournalService(journalRepository)
+	journalService := service.NewJournalService(journalRepository, reactionRepository)
 	journalHandler := handler.NewJournalHandler(journalService, validator)
 
 	commentRepository := repository.NewCommentRepository(s.DB)
-	commentService := service.NewCommentService(commentRepository)
-	commentHandler := handler.NewCommentHandler(commentService, validator)
+	moderationLogRepository := repository.NewModerationLogRepository(s.DB)
+	moderationService := service.NewModerationService(commentRepository, moderationLogRepository)
+	moderationHandler := handler.NewModerationHandler(moderationService, validator)
 
 	topicRepository := repository.NewTopicRepository(s.DB)
 	topicService := service.NewTopicService(topicRepository)
 	topicHandler := handler.NewTopicHandler(topicService, validator)
 
 	forumRepository := repository.NewForumRepository(s.DB)
-	forumService := service.NewForumService(forumRepository, topicRepository)
+	forumService := service.NewForumService(forumRepository, topicRepository, reactionRepository, pseudonyms)
 	forumHandler := handler.NewForumHandler(forumService, validator)
 
-	router := https.NewRouter(e, jwt, https.Handlers{
-		User:    userHandler,
-		Journal: journalHandler,
-		Topic:   topicHandler,
-		Comment: commentHandler,
-		Forum:   forumHandler,
-		Vent:    ventHandler,
+	commentService := service.NewCommentService(commentRepository, forumRepository, reactionRepository, pseudonyms, s.Comment)
+	commentHandler := handler.NewCommentHandler(commentService, validator)
+
+	reactionService := service.NewReactionService(reactionRepository, forumRepository, commentRepository, journalRepository)
+	reactionHandler := handler.NewReactionHandler(reactionService, validator)
+
+	searchRepository := repository.NewSearchRepository(s.DB)
+	searchService := service.NewSearchService(searchRepository)
+	searchHandler := handler.NewSearchHandler(searchService, validator)
+
+	accountRepository := repository.NewAccountRepository(s.DB)
+	accountService := service.NewAccountService(accountRepository, userRepository, twoFactorRepository, s.Account)
+	accountHandler := handler.NewAccountHandler(accountService, validator)
+
+	retentionService := service.NewRetentionService(forumRepository, commentRepository, journalRepository, s.Retention)
+
+	router := https.NewRouter(e, s.JWT, authService, https.Handlers{
+		Auth:       authHandler,
+		TwoFactor:  twoFactorHandler,
+		Identity:   identityHandler,
+		User:       userHandler,
+		Journal:    journalHandler,
+		Topic:      topicHandler,
+		Comment:    commentHandler,
+		Forum:      forumHandler,
+		Vent:       ventHandler,
+		Moderation: moderationHandler,
+		Reaction:   reactionHandler,
+		Search:     searchHandler,
+		Account:    accountHandler,
 	})
 
-	s.DB.AutoMigrate(&domain.User{}, &domain.Journal{}, &domain.Forum{}, &domain.Topic{}, &domain.Comment{})
+	s.DB.AutoMigrate(&domain.User{}, &domain.Journal{}, &domain.Forum{}, &domain.Topic{}, &domain.Comment{}, &domain.SafetyEvent{}, &domain.PromptTemplate{}, &domain.VentReply{}, &domain.ModerationLog{}, &domain.Reaction{}, &domain.DataExport{}, &domain.AccountDeletion{}, &domain.RefreshToken{}, &domain.RevokedToken{}, &domain.UserToken{}, &domain.TwoFactor{}, &domain.RecoveryCode{}, &domain.Identity{}, &domain.OIDCState{})
+
+	if err := migration.Run(s.DB); err != nil {
+		return err
+	}
+
+	if s.Admin.Email != "" {
+		if err := userService.BootstrapAdmin(web.UserBootstrapAdmin{
+			Email:    s.Admin.Email,
+			Password: s.Admin.Password,
+		}); err != nil {
+			return err
+		}
+	}
+
+	go accountService.Run(context.Background())
+	go retentionService.Run(context.Background())
+	go authService.Run(context.Background())
+
+	templates, err := promptTemplateRepository.FindActive()
+	if err != nil {
+		return err
+	}
+
+	for _, t := range templates {
+		if err := s.Prompts.Add(t.Persona, t.Language, t.Version, t.Body, t.Weight); err != nil {
+			return err
+		}
+	}
 
 	return e.StartServer(&http.Server{
 		Addr:    s.Host + ":" + s.Port,
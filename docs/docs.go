@@ -15,43 +15,47 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/auth/login": {
+        "/auth/2fa/disable": {
             "post": {
-                "description": "Authenticate a user and generate a JWT token",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
-                "produces": [
+                "description": "Turn two-factor authentication off with the password and a code of the authenticator or a recovery code",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Auth"
                 ],
-                "summary": "User login",
+                "summary": "Disable two-factor authentication",
                 "parameters": [
                     {
-                        "description": "User Login Request",
-                        "name": "user",
+                        "description": "Password and code",
+                        "name": "disable",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/web.UserLogin"
+                            "$ref": "#/definitions/web.TwoFactorDisable"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/web.UserAuth"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/auth/register": {
+        "/auth/2fa/enable": {
             "post": {
-                "description": "Register a new user with email and password",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirm a code of the secret from setup to enable two-factor authentication. The recovery codes are only shown once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -59,38 +63,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Auth"
                 ],
-                "summary": "Register a new user",
+                "summary": "Enable two-factor authentication",
                 "parameters": [
                     {
-                        "description": "User Register Request",
-                        "name": "user",
+                        "description": "Code of the authenticator",
+                        "name": "enable",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/web.UserRegister"
+                            "$ref": "#/definitions/web.TwoFactorEnable"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.UserResponse"
+                            "$ref": "#/definitions/web.TwoFactorRecoveryCodesResponse"
                         }
                     }
                 }
             }
         },
-        "/comments": {
-            "get": {
+        "/auth/2fa/recovery-codes": {
+            "post": {
                 "security": [
                     {
-                        "Bearer": []
+                        "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve all comments",
+                "description": "Replace the recovery codes with new ones, the previous codes stop working",
                 "consumes": [
                     "application/json"
                 ],
@@ -98,28 +102,58 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Auth"
+                ],
+                "summary": "Regenerate recovery codes",
+                "parameters": [
+                    {
+                        "description": "Code of the authenticator or a recovery code",
+                        "name": "code",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.TwoFactorRecoveryCodes"
+                        }
+                    }
                 ],
-                "summary": "Get all comments",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/web.CommentResponse"
-                            }
+                            "$ref": "#/definitions/web.TwoFactorRecoveryCodesResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/2fa/setup": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a comment",
+                "description": "Create a TOTP secret for the authenticated user. Show the uri as a QR code, two-factor authentication is enabled once a code of it is confirmed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Set up two-factor authentication",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.TwoFactorSetupResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/2fa/verify": {
+            "post": {
+                "description": "Exchange the challenge token of a login with two-factor authentication and a code of the authenticator, or a recovery code, for the session. A challenge can be tried once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -127,68 +161,61 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Auth"
                 ],
-                "summary": "Create a new comment",
+                "summary": "Verify the second factor",
                 "parameters": [
                     {
-                        "description": "Comment Data",
-                        "name": "comment",
+                        "description": "Challenge token and code",
+                        "name": "verify",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/web.CommentCreate"
+                            "$ref": "#/definitions/web.AuthTwoFactorVerify"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.CommentResponse"
+                            "$ref": "#/definitions/web.UserAuth"
                         }
                     }
                 }
             }
         },
-        "/comments/{id}": {
-            "get": {
-                "description": "Retrieve a single comment by ID",
+        "/auth/forgot-password": {
+            "post": {
+                "description": "Mail a link to reset the password. The response is the same whether the email is registered or not.",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Comments"
+                    "Auth"
                 ],
-                "summary": "Get a comment by ID",
+                "summary": "Forgot password",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Comment ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Email of the account",
+                        "name": "email",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.AuthForgotPassword"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/web.CommentResponse"
-                        }
+                    "202": {
+                        "description": "Accepted"
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update a comment",
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Authenticate a user and start a session with a short lived access token and a refresh token. Repeated failures lock the account and the IP address out for a while. Users with two-factor authentication get a web.AuthChallenge to verify instead.",
                 "consumes": [
                     "application/json"
                 ],
@@ -196,23 +223,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Comments"
+                    "Auth"
                 ],
-                "summary": "Update an existing comment",
+                "summary": "User login",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Comment ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated Comment Data",
-                        "name": "comment",
+                        "description": "User Login Request",
+                        "name": "user",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/web.CommentUpdate"
+                            "$ref": "#/definitions/web.UserLogin"
                         }
                     }
                 ],
@@ -220,37 +241,43 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.CommentResponse"
+                            "$ref": "#/definitions/web.UserAuth"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/auth/logout": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove a comment by ID",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Revoke the access token and the session it belongs to",
                 "tags": [
-                    "Comments"
+                    "Auth"
                 ],
-                "summary": "Delete a comment",
-                "parameters": [
+                "summary": "Log out",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/auth/logout-all": {
+            "post": {
+                "security": [
                     {
-                        "type": "integer",
-                        "description": "Comment ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "BearerAuth": []
                     }
                 ],
+                "description": "Revoke every session of the authenticated user and their access tokens",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Log out all devices",
                 "responses": {
                     "204": {
                         "description": "No Content"
@@ -258,69 +285,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/forums": {
+        "/auth/oidc": {
             "get": {
-                "description": "Get all forum posts",
+                "description": "List the names of the OpenID Connect providers users can sign in with",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Forums"
+                    "Auth"
                 ],
-                "summary": "Get All Forums",
+                "summary": "List the OIDC providers",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/web.ForumResponse"
-                            }
+                            "$ref": "#/definitions/web.OIDCProviders"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/auth/oidc/{provider}": {
             "post": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Create a new forum post",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Start the authorization code flow with PKCE at an OpenID Connect provider. Send the user to authorization_url, the provider sends them back to the app with the code and the state to post to the callback.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Forums"
+                    "Auth"
                 ],
-                "summary": "Create Forum",
+                "summary": "Start signing in with a provider",
                 "parameters": [
                     {
-                        "description": "Forum Data",
-                        "name": "forum",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/web.ForumCreate"
-                        }
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.ForumResponse"
+                            "$ref": "#/definitions/web.OIDCStartResponse"
                         }
                     }
                 }
             }
         },
-        "/forums/{id}": {
-            "get": {
-                "description": "Get a forum post by ID",
+        "/auth/oidc/{provider}/callback": {
+            "post": {
+                "description": "Exchange the code and the state the provider returned for a session. The first sign in links the account with the same verified email, or creates one. Users with two-factor authentication get a web.AuthChallenge to verify instead.",
                 "consumes": [
                     "application/json"
                 ],
@@ -328,34 +344,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Forums"
+                    "Auth"
                 ],
-                "summary": "Get Forum by ID",
+                "summary": "Finish signing in with a provider",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Forum ID",
-                        "name": "id",
+                        "description": "Provider name",
+                        "name": "provider",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Code and state",
+                        "name": "callback",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.OIDCCallback"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.ForumResponse"
+                            "$ref": "#/definitions/web.UserAuth"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Update a forum post",
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Exchange a refresh token for a new access token and a new refresh token. Every refresh token can be used once, using one again revokes the session.",
                 "consumes": [
                     "application/json"
                 ],
@@ -363,23 +385,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Forums"
+                    "Auth"
                 ],
-                "summary": "Update Forum",
+                "summary": "Refresh the access token",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Forum ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated Forum Data",
-                        "name": "forum",
+                        "description": "Refresh token",
+                        "name": "refresh",
                         "in": "body",
+                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/web.ForumUpdate"
+                            "$ref": "#/definitions/web.AuthRefresh"
                         }
                     }
                 ],
@@ -387,71 +403,92 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.ForumResponse"
+                            "$ref": "#/definitions/web.UserAuth"
                         }
                     }
                 }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Delete a forum post",
-                "consumes": [
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Register a new user with email and password, and mail them a link to verify the email. Unverified users cannot post forums or comments.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Forums"
+                    "Auth"
                 ],
-                "summary": "Delete Forum",
+                "summary": "Register a new user",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Forum ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "User Register Request",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.UserRegister"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.UserResponse"
+                        }
                     }
                 }
             }
         },
-        "/forums/{id}/topic": {
-            "delete": {
-                "security": [
+        "/auth/reset-password": {
+            "post": {
+                "description": "Set a new password with the token of the link mailed by forgot password. Every session of the user is revoked.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reset password",
+                "parameters": [
                     {
-                        "BearerAuth": []
+                        "description": "Reset token and new password",
+                        "name": "reset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.AuthResetPassword"
+                        }
                     }
                 ],
-                "description": "Remove a topic from a specific forum by its ID",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/auth/verify-email": {
+            "post": {
+                "description": "Verify the email of a user with the token of the link mailed to them. Access tokens issued before carry the old status until they are refreshed.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Forums"
+                    "Auth"
                 ],
-                "summary": "Remove Topic from Forum",
+                "summary": "Verify the email",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Forum ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Remove Topic from Forum",
-                        "name": "topic",
+                        "description": "Verification token",
+                        "name": "token",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/web.ForumRemoveTopic"
+                            "$ref": "#/definitions/web.AuthVerifyEmail"
                         }
                     }
                 ],
@@ -462,24 +499,93 @@ const docTemplate = `{
                 }
             }
         },
-        "/journals": {
+        "/auth/verify-email/resend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mail the authenticated user a new link to verify their email, the previous links stop working",
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Resend the verification email",
+                "responses": {
+                    "202": {
+                        "description": "Accepted"
+                    }
+                }
+            }
+        },
+        "/comments": {
             "get": {
-                "description": "Get all journals",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve all approved public comments, plus every comment of the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Journals"
+                    "Comments"
+                ],
+                "summary": "Get all comments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "name": "authorID",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "forumID",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get All Journals",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/web.JournalResponse"
-                            }
+                            "$ref": "#/definitions/web.PageResponse-web_CommentResponse"
                         }
                     }
                 }
@@ -490,7 +596,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new journal entry",
+                "description": "Create a comment",
                 "consumes": [
                     "application/json"
                 ],
@@ -498,17 +604,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Journals"
+                    "Comments"
                 ],
-                "summary": "Create Journal",
+                "summary": "Create a new comment",
                 "parameters": [
                     {
-                        "description": "Journal Data",
-                        "name": "journal",
+                        "description": "Comment Data",
+                        "name": "comment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/web.JournalCreate"
+                            "$ref": "#/definitions/web.CommentCreate"
                         }
                     }
                 ],
@@ -516,15 +622,20 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/web.JournalResponse"
+                            "$ref": "#/definitions/web.CommentResponse"
                         }
                     }
                 }
             }
         },
-        "/journals/{id}": {
+        "/comments/{id}": {
             "get": {
-                "description": "Get journal by its ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve a single comment by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -532,13 +643,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Journals"
+                    "Comments"
                 ],
-                "summary": "Get Journal by ID",
+                "summary": "Get a comment by ID",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Journal ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -548,7 +659,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.JournalResponse"
+                            "$ref": "#/definitions/web.CommentResponse"
                         }
                     }
                 }
@@ -559,7 +670,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing journal entry",
+                "description": "Update a comment",
                 "consumes": [
                     "application/json"
                 ],
@@ -567,23 +678,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Journals"
+                    "Comments"
                 ],
-                "summary": "Update Journal",
+                "summary": "Update an existing comment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Journal ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated Journal Data",
-                        "name": "journal",
+                        "description": "Updated Comment Data",
+                        "name": "comment",
                         "in": "body",
                         "schema": {
-                            "$ref": "#/definitions/web.JournalUpdate"
+                            "$ref": "#/definitions/web.CommentUpdate"
                         }
                     }
                 ],
@@ -591,7 +702,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.JournalResponse"
+                            "$ref": "#/definitions/web.CommentResponse"
                         }
                     }
                 }
@@ -602,18 +713,21 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a journal entry",
+                "description": "Remove a comment by ID",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Journals"
+                    "Comments"
                 ],
-                "summary": "Delete Journal",
+                "summary": "Delete a comment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Journal ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -626,83 +740,113 @@ const docTemplate = `{
                 }
             }
         },
-        "/topics": {
-            "get": {
-                "description": "Get all topics",
+        "/comments/{id}/reactions/{type}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Leave a support reaction on a comment, reacting twice with the same type has no effect",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Topics"
+                    "Reactions"
+                ],
+                "summary": "React to a comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "hug",
+                            "relate",
+                            "thanks",
+                            "strength",
+                            "hope"
+                        ],
+                        "type": "string",
+                        "description": "Reaction type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get All Topics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/web.TopicResponse"
-                            }
+                            "$ref": "#/definitions/web.ReactionSummary"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new topic",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "Remove a reaction left on a comment",
                 "tags": [
-                    "Topics"
+                    "Reactions"
                 ],
-                "summary": "Create Topic",
+                "summary": "Remove a comment reaction",
                 "parameters": [
                     {
-                        "description": "Topic Data",
-                        "name": "topic",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/web.TopicCreate"
-                        }
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "hug",
+                            "relate",
+                            "thanks",
+                            "strength",
+                            "hope"
+                        ],
+                        "type": "string",
+                        "description": "Reaction type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/web.TopicResponse"
-                        }
+                    "204": {
+                        "description": "No Content"
                     }
                 }
             }
         },
-        "/topics/{id}": {
-            "get": {
-                "description": "Get topic by its ID",
-                "consumes": [
-                    "application/json"
+        "/comments/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Restore a deleted comment or the placeholder it left behind. Authors restore their own comments, moderators restore any comment.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Topics"
+                    "Comments"
                 ],
-                "summary": "Get Topic by ID",
+                "summary": "Restore a comment",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Topic ID",
+                        "description": "Comment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -712,136 +856,131 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.TopicResponse"
+                            "$ref": "#/definitions/web.CommentResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "security": [
+            }
+        },
+        "/forums": {
+            "get": {
+                "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update an existing topic",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get forum posts, filtered by topic or author",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Topics"
+                    "Forums"
                 ],
-                "summary": "Update Topic",
+                "summary": "Get All Forums",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Topic ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "name": "authorID",
+                        "in": "query"
                     },
                     {
-                        "description": "Updated Topic Data",
-                        "name": "topic",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/web.TopicUpdate"
-                        }
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at",
+                            "title"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "topicID",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.TopicResponse"
+                            "$ref": "#/definitions/web.PageResponse-web_ForumResponse"
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a topic",
+                "description": "Create a new forum post",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Topics"
+                    "Forums"
                 ],
-                "summary": "Delete Topic",
+                "summary": "Create Forum",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Topic ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
+                        "description": "Forum Data",
+                        "name": "forum",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.ForumCreate"
+                        }
                     }
-                }
-            }
-        },
-        "/users": {
-            "get": {
-                "description": "Retrieve a list of all users",
-                "produces": [
-                    "application/json"
                 ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Get all users",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/web.UserResponse"
-                            }
+                            "$ref": "#/definitions/web.ForumResponse"
                         }
                     }
                 }
             }
         },
-        "/users/me": {
+        "/forums/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retrieve the details of the currently authenticated user based on the JWT token provided",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Users"
-                ],
-                "summary": "Get current user",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/web.UserResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/users/{id}": {
-            "get": {
-                "description": "Retrieve a user by their ID",
+                "description": "Get a forum post by ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -849,13 +988,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Forums"
                 ],
-                "summary": "Get user by ID",
+                "summary": "Get Forum by ID",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
+                        "type": "string",
+                        "description": "Forum ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -865,7 +1004,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.UserResponse"
+                            "$ref": "#/definitions/web.ForumResponse"
                         }
                     }
                 }
@@ -876,7 +1015,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a user's information",
+                "description": "Update a forum post",
                 "consumes": [
                     "application/json"
                 ],
@@ -884,23 +1023,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Users"
+                    "Forums"
                 ],
-                "summary": "Update a user",
+                "summary": "Update Forum",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
+                        "type": "string",
+                        "description": "Forum ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "User Update Request",
-                        "name": "user",
+                        "description": "Updated Forum Data",
+                        "name": "forum",
                         "in": "body",
                         "schema": {
-                            "$ref": "#/definitions/web.UserUpdate"
+                            "$ref": "#/definitions/web.ForumUpdate"
                         }
                     }
                 ],
@@ -908,7 +1047,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.UserResponse"
+                            "$ref": "#/definitions/web.ForumResponse"
                         }
                     }
                 }
@@ -919,21 +1058,18 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a user by their ID",
+                "description": "Delete a forum post",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Users"
+                    "Forums"
                 ],
-                "summary": "Delete a user",
+                "summary": "Delete Forum",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "User ID",
+                        "type": "string",
+                        "description": "Forum ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -946,40 +1082,136 @@ const docTemplate = `{
                 }
             }
         },
-        "/vents": {
-            "post": {
+        "/forums/{id}/comments": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Start a chat session with AI for venting (confiding)",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Retrieve the thread of a forum, as a tree with the first replies of every comment embedded or as a flat chronological list",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Vents"
+                    "Comments"
                 ],
-                "summary": "Chat with AI",
+                "summary": "Get the comments of a forum",
                 "parameters": [
                     {
-                        "description": "Vent Request",
-                        "name": "chat",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Forum ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "forumID",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "tree",
+                            "flat"
+                        ],
+                        "type": "string",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "parentID",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "replyLimit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.VentRequest"
+                            "$ref": "#/definitions/web.PageResponse-web_CommentResponse"
                         }
                     }
+                }
+            }
+        },
+        "/forums/{id}/reactions/{type}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Leave a support reaction on a forum post, reacting twice with the same type has no effect",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reactions"
+                ],
+                "summary": "React to a forum",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Forum ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "hug",
+                            "relate",
+                            "thanks",
+                            "strength",
+                            "hope"
+                        ],
+                        "type": "string",
+                        "description": "Reaction type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/web.VentResponse"
+                            "$ref": "#/definitions/web.ReactionSummary"
                         }
                     }
                 }
@@ -990,110 +1222,2439 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Clear the chat history for the current user",
+                "description": "Remove a reaction left on a forum post",
                 "tags": [
-                    "Vents"
+                    "Reactions"
+                ],
+                "summary": "Remove a forum reaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Forum ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "hug",
+                            "relate",
+                            "thanks",
+                            "strength",
+                            "hope"
+                        ],
+                        "type": "string",
+                        "description": "Reaction type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Clear chat history",
                 "responses": {
                     "204": {
                         "description": "No Content"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.CommentVisibility": {
-            "type": "string",
-            "enum": [
-                "review",
-                "public",
-                "private"
-            ],
-            "x-enum-varnames": [
-                "ReviewComment",
+        },
+        "/forums/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a deleted forum with the comments deleted along with it. Authors restore their own forums, moderators restore any forum.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Forums"
+                ],
+                "summary": "Restore Forum",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Forum ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ForumResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/forums/{id}/topic": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a topic from a specific forum by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Forums"
+                ],
+                "summary": "Remove Topic from Forum",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Forum ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Remove Topic from Forum",
+                        "name": "topic",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.ForumRemoveTopic"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/journals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all public journals, plus the private journals of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Get All Journals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "happy",
+                            "good",
+                            "normal",
+                            "sad",
+                            "angry"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "Happy",
+                            "Good",
+                            "Normal",
+                            "Sad",
+                            "Angry"
+                        ],
+                        "name": "mood",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at",
+                            "mood"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.PageResponse-web_JournalResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new journal entry",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Create Journal",
+                "parameters": [
+                    {
+                        "description": "Journal Data",
+                        "name": "journal",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.JournalCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.JournalResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/journals/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get journal by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Get Journal by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.JournalResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing journal entry",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Update Journal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Journal Data",
+                        "name": "journal",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/web.JournalUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.JournalResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a journal entry",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Delete Journal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/journals/{id}/reactions/{type}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Leave a support reaction on a public journal, reacting twice with the same type has no effect",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Reactions"
+                ],
+                "summary": "React to a journal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "hug",
+                            "relate",
+                            "thanks",
+                            "strength",
+                            "hope"
+                        ],
+                        "type": "string",
+                        "description": "Reaction type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.ReactionSummary"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a reaction left on a journal",
+                "tags": [
+                    "Reactions"
+                ],
+                "summary": "Remove a journal reaction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "hug",
+                            "relate",
+                            "thanks",
+                            "strength",
+                            "hope"
+                        ],
+                        "type": "string",
+                        "description": "Reaction type",
+                        "name": "type",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/journals/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a deleted journal. Authors restore their own journals, moderators restore any public journal.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Restore Journal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Journal ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.JournalResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/comments": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the comments waiting for review, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Get pending comments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.PageResponse-web_CommentResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/comments/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Publish a comment that is waiting for review",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Approve a comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approval Data",
+                        "name": "comment",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/web.CommentApprove"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/moderation/comments/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reject a comment that is waiting for review",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Reject a comment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Comment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rejection Data",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.CommentReject"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/moderation/logs": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the audit trail of moderation decisions, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Moderation"
+                ],
+                "summary": "Get moderation logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.PageResponse-web_ModerationLogResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/search": {
+            "get": {
+                "description": "Full-text search across forums, public journals and topics, best matches first. Snippets are HTML escaped and highlight the matches with \u003cmark\u003e tags. Filtering by topic only returns the forums of that topic.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Search"
+                ],
+                "summary": "Search",
+                "parameters": [
+                    {
+                        "enum": [
+                            "english",
+                            "indonesian"
+                        ],
+                        "type": "string",
+                        "name": "language",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "maxLength": 200,
+                        "type": "string",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "name": "topicID",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "forum",
+                            "journal",
+                            "topic"
+                        ],
+                        "type": "string",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.PageResponse-web_SearchResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics": {
+            "get": {
+                "description": "Get all topics, optionally filtered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Topics"
+                ],
+                "summary": "Get All Topics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "name"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.PageResponse-web_TopicResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new topic",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Topics"
+                ],
+                "summary": "Create Topic",
+                "parameters": [
+                    {
+                        "description": "Topic Data",
+                        "name": "topic",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.TopicCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.TopicResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topics/{id}": {
+            "get": {
+                "description": "Get topic by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Topics"
+                ],
+                "summary": "Get Topic by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Topic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.TopicResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update an existing topic",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Topics"
+                ],
+                "summary": "Update Topic",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Topic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated Topic Data",
+                        "name": "topic",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.TopicUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.TopicResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a topic",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Topics"
+                ],
+                "summary": "Delete Topic",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Topic ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users": {
+            "get": {
+                "description": "Retrieve a list of users, optionally filtered by name",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get all users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "name"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.PageResponse-web_UserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retrieve the details of the currently authenticated user based on the JWT token provided",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.UserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/deletion": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the deletion scheduled for the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get the scheduled deletion",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.AccountDeletionResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel the deletion scheduled for the authenticated user during the grace period",
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Cancel the scheduled deletion",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/me/exports": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Queue a ZIP archive of the profile, journals, forums, comments and reactions of the authenticated user. The export already waiting in the queue is returned when there is one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Request a data export",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/web.DataExportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/exports/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the status of a data export of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get a data export",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.DataExportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/exports/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download the ZIP archive of a ready data export of the authenticated user",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Download a data export",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Export ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/identities": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the OpenID Connect identities connected to the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List connected identities",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.IdentityResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/identities/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Disconnect an identity from the authenticated user. Users without a password cannot disconnect their last identity.",
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Disconnect an identity",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Identity ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/users/me/identities/{provider}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start signing in at an OpenID Connect provider to connect it to the authenticated user. Post the code and the state the provider returns to the connect callback.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Connect an identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.OIDCStartResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/identities/{provider}/callback": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Connect the identity the authenticated user signed in with at the provider",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Finish connecting an identity",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Code and state",
+                        "name": "callback",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.OIDCCallback"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/web.IdentityResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/journals/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Download every journal of the authenticated user as JSON, CSV or Markdown with one section per day",
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "text/markdown"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Export Journals",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "markdown",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "name": "format",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "name": "timezone",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.JournalEntry"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/journals/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Import a JSON or CSV journal export, picked by the Content-Type of the body. Every entry is validated before any is created, and entries with the same creation time and content as an existing journal are skipped, so importing a file twice is a no-op.",
+                "consumes": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Import Journals",
+                "parameters": [
+                    {
+                        "description": "Journal export",
+                        "name": "journals",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/web.JournalEntry"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.JournalImportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/me/mood-insights": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the mood distributions per day, week and month, the daily mood score, the journaling streaks and the most frequent mood by weekday of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Get Mood Insights",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "timezone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.MoodInsightsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "description": "Retrieve a user by their ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get user by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.UserResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a user's information. Changing the email or the password asks for the current password, and the two-factor code when it is enabled. A new email has to be verified again and a new password ends the other sessions.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User Update Request",
+                        "name": "user",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/web.UserUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.UserResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedule the deletion of the authenticated user after confirming their password, and their two-factor code when it is enabled. Accounts without a password must have logged in within the re-authentication window. The account is erased once the grace period is over, unless the deletion is cancelled first. The anonymize mode keeps forums and comments under a \"Deleted user\" account, the remove mode erases their text too.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Password and erasure mode",
+                        "name": "deletion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.AccountDeletionCreate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/web.AccountDeletionResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/journals": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the public journals of a user, or all of them when the user is the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Journals"
+                ],
+                "summary": "Get Journals by User",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "happy",
+                            "good",
+                            "normal",
+                            "sad",
+                            "angry"
+                        ],
+                        "type": "string",
+                        "x-enum-varnames": [
+                            "Happy",
+                            "Good",
+                            "Normal",
+                            "Sad",
+                            "Angry"
+                        ],
+                        "name": "mood",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "name": "ownerID",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "created_at",
+                            "updated_at",
+                            "mood"
+                        ],
+                        "type": "string",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.PageResponse-web_JournalResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Promote or demote a user. Only admins can do this.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Update a user's role",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "User Role Request",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.UserUpdateRole"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.UserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vents": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a chat session with AI for venting (confiding)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Vents"
+                ],
+                "summary": "Chat with AI",
+                "parameters": [
+                    {
+                        "description": "Vent Request",
+                        "name": "chat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.VentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.VentResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Clear the chat history for the current user",
+                "tags": [
+                    "Vents"
+                ],
+                "summary": "Clear chat history",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/vents/stream": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stream the AI reply as Server-Sent Events. Each \"message\" event carries a web.VentChunk and the final \"done\" event carries the full web.VentResponse. Text is screened before it is sent, a sentence at a time. When the reply turns high risk the stream stops before the risky text and the \"done\" event carries the safe message. Clients that do not accept text/event-stream receive a regular JSON response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/event-stream",
+                    "application/json"
+                ],
+                "tags": [
+                    "Vents"
+                ],
+                "summary": "Stream a chat reply from AI",
+                "parameters": [
+                    {
+                        "description": "Vent Request",
+                        "name": "chat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/web.VentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/web.VentResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.CommentVisibility": {
+            "type": "string",
+            "enum": [
+                "review",
+                "public",
+                "private",
+                "rejected"
+            ],
+            "x-enum-varnames": [
+                "ReviewComment",
                 "PublicComment",
-                "PrivateComment"
+                "PrivateComment",
+                "RejectedComment"
+            ]
+        },
+        "domain.ErasureMode": {
+            "type": "string",
+            "enum": [
+                "anonymize",
+                "remove"
+            ],
+            "x-enum-varnames": [
+                "AnonymizeErasure",
+                "RemoveErasure"
+            ]
+        },
+        "domain.ExportStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "running",
+                "ready",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "PendingExport",
+                "RunningExport",
+                "ReadyExport",
+                "FailedExport"
+            ]
+        },
+        "domain.JournalMood": {
+            "type": "string",
+            "enum": [
+                "happy",
+                "good",
+                "normal",
+                "sad",
+                "angry"
+            ],
+            "x-enum-varnames": [
+                "Happy",
+                "Good",
+                "Normal",
+                "Sad",
+                "Angry"
+            ]
+        },
+        "domain.JournalVisibility": {
+            "type": "string",
+            "enum": [
+                "private",
+                "public"
+            ],
+            "x-enum-varnames": [
+                "PrivateJournal",
+                "PublicJournal"
+            ]
+        },
+        "domain.ModerationAction": {
+            "type": "string",
+            "enum": [
+                "approve",
+                "reject",
+                "restore"
+            ],
+            "x-enum-varnames": [
+                "ApproveAction",
+                "RejectAction",
+                "RestoreAction"
             ]
         },
-        "domain.JournalMood": {
-            "type": "string",
-            "enum": [
-                "happy",
-                "good",
-                "normal",
-                "sad",
-                "angry"
+        "domain.ReactionType": {
+            "type": "string",
+            "enum": [
+                "hug",
+                "relate",
+                "thanks",
+                "strength",
+                "hope"
+            ],
+            "x-enum-varnames": [
+                "HugReaction",
+                "RelateReaction",
+                "ThanksReaction",
+                "StrengthReaction",
+                "HopeReaction"
+            ]
+        },
+        "domain.UserRole": {
+            "type": "string",
+            "enum": [
+                "user",
+                "moderator",
+                "admin",
+                "deleted"
+            ],
+            "x-enum-varnames": [
+                "RegularUser",
+                "ModeratorUser",
+                "AdminUser",
+                "DeletedUser"
+            ]
+        },
+        "safety.Category": {
+            "type": "string",
+            "enum": [
+                "suicide",
+                "self_harm",
+                "violence"
+            ],
+            "x-enum-varnames": [
+                "Suicide",
+                "SelfHarm",
+                "Violence"
+            ]
+        },
+        "safety.Level": {
+            "type": "string",
+            "enum": [
+                "none",
+                "moderate",
+                "high"
+            ],
+            "x-enum-varnames": [
+                "NoRisk",
+                "ModerateRisk",
+                "HighRisk"
+            ]
+        },
+        "safety.Resource": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.AccountDeletionCreate": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "enum": [
+                        "anonymize",
+                        "remove"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.ErasureMode"
+                        }
+                    ]
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.AccountDeletionResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "mode": {
+                    "$ref": "#/definitions/domain.ErasureMode"
+                },
+                "scheduled_for": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.AuthForgotPassword": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.AuthRefresh": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.AuthResetPassword": {
+            "type": "object",
+            "required": [
+                "password",
+                "token"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "minLength": 8
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.AuthTwoFactorVerify": {
+            "type": "object",
+            "required": [
+                "challenge_token",
+                "code"
             ],
-            "x-enum-varnames": [
-                "Happy",
-                "Good",
-                "Normal",
-                "Sad",
-                "Angry"
-            ]
+            "properties": {
+                "challenge_token": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.AuthVerifyEmail": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.CommentApprove": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.CommentCreate": {
+            "type": "object",
+            "required": [
+                "content",
+                "forum_id",
+                "visibility"
+            ],
+            "properties": {
+                "anonymous": {
+                    "type": "boolean"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "forum_id": {
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "enum": [
+                        "review",
+                        "public",
+                        "private"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CommentVisibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "web.CommentReject": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.CommentReplies": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.CommentResponse"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.CommentResponse": {
+            "type": "object",
+            "properties": {
+                "anonymous": {
+                    "type": "boolean"
+                },
+                "comment": {
+                    "$ref": "#/definitions/domain.CommentVisibility"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "boolean"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "depth": {
+                    "type": "integer"
+                },
+                "forum_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "parent_id": {
+                    "type": "integer"
+                },
+                "pseudonym": {
+                    "type": "string"
+                },
+                "reactions": {
+                    "$ref": "#/definitions/web.ReactionSummary"
+                },
+                "replies": {
+                    "$ref": "#/definitions/web.CommentReplies"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/web.UserResponse"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.CommentUpdate": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "enum": [
+                        "review",
+                        "public",
+                        "private"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CommentVisibility"
+                        }
+                    ]
+                }
+            }
+        },
+        "web.DataExportResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.ExportStatus"
+                }
+            }
+        },
+        "web.ForumCreate": {
+            "type": "object",
+            "required": [
+                "content",
+                "title",
+                "topics"
+            ],
+            "properties": {
+                "anonymous": {
+                    "type": "boolean"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "topics": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "web.ForumRemoveTopic": {
+            "type": "object",
+            "required": [
+                "topic_id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "topic_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.ForumResponse": {
+            "type": "object",
+            "properties": {
+                "anonymous": {
+                    "type": "boolean"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pseudonym": {
+                    "type": "string"
+                },
+                "reactions": {
+                    "$ref": "#/definitions/web.ReactionSummary"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "topics": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.TopicResponse"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/web.UserResponse"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.ForumUpdate": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "topics": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
         },
-        "domain.JournalVisibility": {
-            "type": "string",
-            "enum": [
-                "private",
-                "public"
-            ],
-            "x-enum-varnames": [
-                "PrivateJournal",
-                "PublicJournal"
-            ]
+        "web.IdentityResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "provider": {
+                    "type": "string"
+                }
+            }
         },
-        "web.CommentCreate": {
+        "web.JournalCreate": {
             "type": "object",
             "required": [
                 "content",
-                "forum_id",
+                "mood",
                 "visibility"
             ],
             "properties": {
                 "content": {
                     "type": "string"
                 },
-                "forum_id": {
-                    "type": "integer"
-                },
-                "user_id": {
-                    "type": "integer"
+                "mood": {
+                    "enum": [
+                        "happy",
+                        "good",
+                        "normal",
+                        "sad",
+                        "angry"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.JournalMood"
+                        }
+                    ]
                 },
                 "visibility": {
                     "enum": [
-                        "review",
-                        "public",
-                        "private"
+                        "private",
+                        "public"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.CommentVisibility"
+                            "$ref": "#/definitions/domain.JournalVisibility"
                         }
                     ]
                 }
             }
         },
-        "web.CommentResponse": {
+        "web.JournalEntry": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "$ref": "#/definitions/domain.CommentVisibility"
-                },
                 "content": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "forum_id": {
+                "id": {
+                    "type": "integer"
+                },
+                "mood": {
+                    "$ref": "#/definitions/domain.JournalMood"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/domain.JournalVisibility"
+                }
+            }
+        },
+        "web.JournalImportResponse": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "type": "integer"
+                },
+                "skipped": {
                     "type": "integer"
+                }
+            }
+        },
+        "web.JournalResponse": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
+                "mood": {
+                    "$ref": "#/definitions/domain.JournalMood"
+                },
+                "reactions": {
+                    "$ref": "#/definitions/web.ReactionSummary"
+                },
                 "updated_at": {
                     "type": "string"
                 },
@@ -1102,10 +3663,13 @@ const docTemplate = `{
                 },
                 "user_id": {
                     "type": "integer"
+                },
+                "visibility": {
+                    "$ref": "#/definitions/domain.JournalVisibility"
                 }
             }
         },
-        "web.CommentUpdate": {
+        "web.JournalUpdate": {
             "type": "object",
             "properties": {
                 "content": {
@@ -1114,218 +3678,378 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
-                "user_id": {
-                    "type": "integer"
-                },
-                "visibility": {
+                "mood": {
                     "enum": [
-                        "review",
-                        "public",
-                        "private"
+                        "happy",
+                        "good",
+                        "normal",
+                        "sad",
+                        "angry"
                     ],
                     "allOf": [
                         {
-                            "$ref": "#/definitions/domain.CommentVisibility"
+                            "$ref": "#/definitions/domain.JournalMood"
                         }
                     ]
+                },
+                "visibility": {
+                    "$ref": "#/definitions/domain.JournalVisibility"
                 }
             }
         },
-        "web.ForumCreate": {
+        "web.ModerationLogResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/domain.ModerationAction"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "moderator_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "integer"
+                },
+                "target_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.MoodDistribution": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "period": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.MoodInsightsResponse": {
+            "type": "object",
+            "properties": {
+                "daily": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.MoodDistribution"
+                    }
+                },
+                "from": {
+                    "type": "string"
+                },
+                "monthly": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.MoodDistribution"
+                    }
+                },
+                "scores": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.MoodScore"
+                    }
+                },
+                "streaks": {
+                    "$ref": "#/definitions/web.MoodStreaks"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "weekdays": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.WeekdayMood"
+                    }
+                },
+                "weekly": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.MoodDistribution"
+                    }
+                }
+            }
+        },
+        "web.MoodScore": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                }
+            }
+        },
+        "web.MoodStreaks": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "type": "integer"
+                },
+                "last_entry": {
+                    "type": "string"
+                },
+                "longest": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.OIDCCallback": {
             "type": "object",
             "required": [
-                "content",
-                "title",
-                "topics"
+                "code",
+                "state"
             ],
             "properties": {
-                "content": {
+                "code": {
                     "type": "string"
                 },
-                "title": {
+                "provider": {
                     "type": "string"
                 },
-                "topics": {
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.OIDCProviders": {
+            "type": "object",
+            "properties": {
+                "providers": {
                     "type": "array",
                     "items": {
-                        "type": "integer"
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "web.OIDCStartResponse": {
+            "type": "object",
+            "properties": {
+                "authorization_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.PageResponse-web_CommentResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.CommentResponse"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "web.PageResponse-web_ForumResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.ForumResponse"
                     }
                 },
-                "user_id": {
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "web.ForumRemoveTopic": {
+        "web.PageResponse-web_JournalResponse": {
             "type": "object",
-            "required": [
-                "topic_id"
-            ],
             "properties": {
-                "id": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.JournalResponse"
+                    }
+                },
+                "limit": {
                     "type": "integer"
                 },
-                "topic_id": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
                     "type": "integer"
                 },
-                "user_id": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "web.ForumResponse": {
+        "web.PageResponse-web_ModerationLogResponse": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "title": {
-                    "type": "string"
-                },
-                "topics": {
+                "data": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/web.TopicResponse"
+                        "$ref": "#/definitions/web.ModerationLogResponse"
                     }
                 },
-                "updated_at": {
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/web.UserResponse"
+                "page": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "web.ForumUpdate": {
+        "web.PageResponse-web_SearchResponse": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.SearchResponse"
+                    }
                 },
-                "id": {
+                "limit": {
                     "type": "integer"
                 },
-                "title": {
+                "next_cursor": {
                     "type": "string"
                 },
-                "topics": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "page": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "web.JournalCreate": {
+        "web.PageResponse-web_TopicResponse": {
             "type": "object",
-            "required": [
-                "content",
-                "mood",
-                "visibility"
-            ],
             "properties": {
-                "content": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.TopicResponse"
+                    }
                 },
-                "mood": {
-                    "enum": [
-                        "happy",
-                        "good",
-                        "normal",
-                        "sad",
-                        "angry"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.JournalMood"
-                        }
-                    ]
+                "limit": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "page": {
                     "type": "integer"
                 },
-                "visibility": {
-                    "enum": [
-                        "private",
-                        "public"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.JournalVisibility"
-                        }
-                    ]
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "web.JournalResponse": {
+        "web.PageResponse-web_UserResponse": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/web.UserResponse"
+                    }
                 },
-                "id": {
+                "limit": {
                     "type": "integer"
                 },
-                "mood": {
-                    "$ref": "#/definitions/domain.JournalMood"
-                },
-                "updated_at": {
+                "next_cursor": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/web.UserResponse"
+                "page": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "total": {
                     "type": "integer"
+                }
+            }
+        },
+        "web.ReactionSummary": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
                 },
-                "visibility": {
-                    "$ref": "#/definitions/domain.JournalVisibility"
+                "mine": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ReactionType"
+                    }
                 }
             }
         },
-        "web.JournalUpdate": {
+        "web.SearchResponse": {
             "type": "object",
             "properties": {
-                "content": {
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "mood": {
-                    "enum": [
-                        "happy",
-                        "good",
-                        "normal",
-                        "sad",
-                        "angry"
-                    ],
-                    "allOf": [
-                        {
-                            "$ref": "#/definitions/domain.JournalMood"
-                        }
-                    ]
+                "rank": {
+                    "type": "number"
                 },
-                "user_id": {
-                    "type": "integer"
+                "snippet": {
+                    "type": "string"
                 },
-                "visibility": {
-                    "$ref": "#/definitions/domain.JournalVisibility"
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
                 }
             }
         },
@@ -1381,17 +4105,88 @@ const docTemplate = `{
                 }
             }
         },
+        "web.TwoFactorDisable": {
+            "type": "object",
+            "required": [
+                "code",
+                "password"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.TwoFactorEnable": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.TwoFactorRecoveryCodes": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.TwoFactorRecoveryCodesResponse": {
+            "type": "object",
+            "properties": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "web.TwoFactorSetupResponse": {
+            "type": "object",
+            "properties": {
+                "secret": {
+                    "type": "string"
+                },
+                "uri": {
+                    "type": "string"
+                }
+            }
+        },
         "web.UserAuth": {
             "type": "object",
             "properties": {
+                "expires_at": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "role": {
+                    "$ref": "#/definitions/domain.UserRole"
+                },
                 "token": {
                     "type": "string"
+                },
+                "verified": {
+                    "type": "boolean"
                 }
             }
         },
@@ -1448,14 +4243,27 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "role": {
+                    "$ref": "#/definitions/domain.UserRole"
+                },
                 "updated_at": {
                     "type": "string"
+                },
+                "verified_at": {
+                    "type": "string"
                 }
             }
         },
         "web.UserUpdate": {
             "type": "object",
             "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "current_password": {
+                    "description": "CurrentPassword, and Code when two-factor authentication is enabled,\nconfirm a change of email or password.",
+                    "type": "string"
+                },
                 "email": {
                     "type": "string"
                 },
@@ -1469,9 +4277,29 @@ const docTemplate = `{
                 "password": {
                     "type": "string",
                     "maxLength": 32
-                },
-                "user_id": {
+                }
+            }
+        },
+        "web.UserUpdateRole": {
+            "type": "object",
+            "required": [
+                "role"
+            ],
+            "properties": {
+                "id": {
                     "type": "integer"
+                },
+                "role": {
+                    "enum": [
+                        "user",
+                        "moderator",
+                        "admin"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.UserRole"
+                        }
+                    ]
                 }
             }
         },
@@ -1481,8 +4309,20 @@ const docTemplate = `{
                 "message"
             ],
             "properties": {
+                "language": {
+                    "type": "string",
+                    "enum": [
+                        "id",
+                        "en"
+                    ]
+                },
                 "message": {
                     "type": "string"
+                },
+                "persona": {
+                    "type": "string",
+                    "maxLength": 32,
+                    "example": "listener"
                 }
             }
         },
@@ -1491,6 +4331,46 @@ const docTemplate = `{
             "properties": {
                 "message": {
                     "type": "string"
+                },
+                "persona": {
+                    "type": "string"
+                },
+                "safety": {
+                    "$ref": "#/definitions/web.VentSafety"
+                },
+                "template_version": {
+                    "type": "string"
+                }
+            }
+        },
+        "web.VentSafety": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "$ref": "#/definitions/safety.Category"
+                },
+                "level": {
+                    "$ref": "#/definitions/safety.Level"
+                },
+                "resources": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/safety.Resource"
+                    }
+                }
+            }
+        },
+        "web.WeekdayMood": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "integer"
+                },
+                "mood": {
+                    "$ref": "#/definitions/domain.JournalMood"
+                },
+                "weekday": {
+                    "type": "string"
                 }
             }
         }
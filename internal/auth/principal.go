@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/util"
+)
+
+// Principal is the user a request is made by, as told by its access token.
+type Principal struct {
+	UserID uint
+	Role   domain.UserRole
+	// Verified tells whether the user had verified their email when the
+	// token was issued.
+	Verified  bool
+	SessionID string
+	// TokenID is the jti of the access token, which lets it be revoked.
+	TokenID   string
+	ExpiresAt time.Time
+	// AuthenticatedAt is when the user logged in to start the session.
+	AuthenticatedAt time.Time
+}
+
+// NewPrincipal returns the principal of the claims of an access token.
+func NewPrincipal(claims *util.Claims) Principal {
+	principal := Principal{
+		UserID:    claims.UserID,
+		Role:      domain.UserRole(claims.Role),
+		Verified:  claims.Verified,
+		SessionID: claims.SessionID,
+		TokenID:   claims.ID,
+	}
+	if claims.ExpiresAt != nil {
+		principal.ExpiresAt = claims.ExpiresAt.Time
+	}
+	if claims.AuthTime != nil {
+		principal.AuthenticatedAt = claims.AuthTime.Time
+	}
+	return principal
+}
+
+// HasRole tells whether the principal has one of roles.
+func (p Principal) HasRole(roles ...domain.UserRole) bool {
+	return slices.Contains(roles, p.Role)
+}
+
+type contextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the principal ctx carries, false when the request was
+// made without an access token.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(Principal)
+	return principal, ok
+}
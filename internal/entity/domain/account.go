@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+type ExportStatus string
+type ErasureMode string
+
+const (
+	PendingExport ExportStatus = "pending"
+	RunningExport ExportStatus = "running"
+	ReadyExport   ExportStatus = "ready"
+	FailedExport  ExportStatus = "failed"
+)
+
+const (
+	// AnonymizeErasure hands the forums and comments of an erased account
+	// over to the deleted user, keeping the threads intact.
+	AnonymizeErasure ErasureMode = "anonymize"
+	// RemoveErasure erases the text of the forums and comments too. Posts
+	// others replied to are kept as placeholders owned by the deleted user.
+	RemoveErasure ErasureMode = "remove"
+)
+
+// DeletedUserEmail is the email of the DeletedUser account. It has no
+// password, so it cannot log in, and the address cannot be signed up with.
+const DeletedUserEmail = "deleted@zense.invalid"
+
+// DataExport is a "download my data" job. File is the path of the archive
+// once the job is ready, it is removed when the export expires.
+type DataExport struct {
+	ID        uint
+	UserID    uint         `gorm:"index"`
+	Status    ExportStatus `gorm:"index"`
+	File      string
+	Error     string
+	ExpiresAt *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// AccountDeletion is a scheduled account erasure, carried out once
+// ScheduledFor has passed unless the user cancels it first.
+type AccountDeletion struct {
+	ID           uint
+	UserID       uint `gorm:"uniqueIndex"`
+	Mode         ErasureMode
+	ScheduledFor time.Time `gorm:"index"`
+	CreatedAt    time.Time
+}
@@ -1,22 +1,36 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type CommentVisibility string
 
 const (
-	ReviewComment  CommentVisibility = "review"
-	PublicComment  CommentVisibility = "public"
-	PrivateComment CommentVisibility = "private"
+	ReviewComment   CommentVisibility = "review"
+	PublicComment   CommentVisibility = "public"
+	PrivateComment  CommentVisibility = "private"
+	RejectedComment CommentVisibility = "rejected"
 )
 
+// DeletedCommentContent is shown instead of the content of a deleted comment
+// that still has replies, so the rest of the thread survives.
+const DeletedCommentContent = "[deleted]"
+
 type Comment struct {
 	ID         uint
 	UserID     uint
 	ForumID    uint
+	ParentID   *uint `gorm:"index"`
+	Depth      int
 	Content    string
+	Deleted    bool
+	Anonymous  bool
 	Visibility CommentVisibility `gorm:"default:'review'" sql:"type:visibility"`
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
-  User User
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	User       User
 }
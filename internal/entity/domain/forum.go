@@ -1,14 +1,24 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DeletedForumContent replaces the title and content of an erased forum that
+// others commented on, so their comments survive.
+const DeletedForumContent = "[deleted]"
 
 type Forum struct {
 	ID        uint
 	UserID    uint
 	Title     string
 	Content   string
+	Anonymous bool
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 	User      User
 	Comments  []Comment
 	Topics    []Topic `gorm:"many2many:forum_topics"`
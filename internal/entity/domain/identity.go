@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+// Identity links a user to their account at an OpenID Connect provider,
+// Subject is the ID the provider knows them by.
+type Identity struct {
+	ID        uint
+	UserID    uint   `gorm:"index"`
+	Provider  string `gorm:"uniqueIndex:idx_identities_subject"`
+	Subject   string `gorm:"uniqueIndex:idx_identities_subject"`
+	Email     string
+	CreatedAt time.Time
+}
+
+// OIDCState is a sign in started at a provider, waiting for the user to come
+// back. UserID is set when a signed in user connects an identity. Only the
+// SHA-256 of the state is stored.
+type OIDCState struct {
+	ID        uint
+	Hash      string `gorm:"uniqueIndex"`
+	Provider  string
+	UserID    *uint
+	Nonce     string
+	Verifier  string
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+func (OIDCState) TableName() string {
+	return "oidc_states"
+}
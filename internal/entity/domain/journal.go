@@ -1,6 +1,10 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type JournalMood string
 type JournalVisibility string
@@ -26,5 +30,6 @@ type Journal struct {
 	Visibility JournalVisibility `gorm:"default:'private'" sql:"type:visibility"`
 	CreatedAt  time.Time
 	UpdatedAt  time.Time
+	DeletedAt  gorm.DeletedAt `gorm:"index"`
   User User
 }
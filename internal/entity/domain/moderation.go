@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+type ModerationAction string
+
+const (
+	ApproveAction ModerationAction = "approve"
+	RejectAction  ModerationAction = "reject"
+	RestoreAction ModerationAction = "restore"
+)
+
+// ModerationLog is the audit trail of every decision taken by a moderator.
+type ModerationLog struct {
+	ID          uint
+	ModeratorID uint
+	TargetType  string
+	TargetID    uint
+	Action      ModerationAction
+	Reason      string
+	CreatedAt   time.Time
+}
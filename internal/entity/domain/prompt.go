@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// PromptTemplate is a text/template body for the vent AI. Several active
+// versions of the same persona and language are split by Weight for A/B tests.
+type PromptTemplate struct {
+	ID        uint
+	Persona   string `gorm:"uniqueIndex:idx_prompt_template_version"`
+	Language  string `gorm:"uniqueIndex:idx_prompt_template_version"`
+	Version   string `gorm:"uniqueIndex:idx_prompt_template_version"`
+	Body      string
+	Weight    int  `gorm:"default:1"`
+	Active    bool `gorm:"default:true"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,32 @@
+package domain
+
+import "time"
+
+type ReactionType string
+
+const (
+	HugReaction      ReactionType = "hug"
+	RelateReaction   ReactionType = "relate"
+	ThanksReaction   ReactionType = "thanks"
+	StrengthReaction ReactionType = "strength"
+	HopeReaction     ReactionType = "hope"
+)
+
+type ReactionTarget string
+
+const (
+	ForumTarget   ReactionTarget = "forum"
+	CommentTarget ReactionTarget = "comment"
+	JournalTarget ReactionTarget = "journal"
+)
+
+// Reaction is a support signal left by a user on a forum, a comment or a
+// public journal. A user leaves at most one reaction of each type per target.
+type Reaction struct {
+	ID         uint
+	UserID     uint           `gorm:"uniqueIndex:idx_reactions_user_target"`
+	TargetType ReactionTarget `gorm:"uniqueIndex:idx_reactions_user_target;index:idx_reactions_target"`
+	TargetID   uint           `gorm:"uniqueIndex:idx_reactions_user_target;index:idx_reactions_target"`
+	Type       ReactionType   `gorm:"uniqueIndex:idx_reactions_user_target"`
+	CreatedAt  time.Time
+}
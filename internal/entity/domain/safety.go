@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+// SafetyEvent records that a vent message was flagged by the safety layer.
+// The message text is intentionally not stored.
+type SafetyEvent struct {
+	ID        uint
+	UserID    uint
+	Stage     string
+	Level     string
+	Category  string
+	Rule      string
+	Language  string
+	CreatedAt time.Time
+}
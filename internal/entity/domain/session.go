@@ -0,0 +1,55 @@
+package domain
+
+import "time"
+
+// RefreshToken is one token of a session. Logging in starts a session, and
+// every refresh rotates its token to a new one of the same session. Only the
+// SHA-256 of the token is stored.
+type RefreshToken struct {
+	ID        uint
+	UserID    uint   `gorm:"index"`
+	SessionID string `gorm:"index"`
+	Hash      string `gorm:"uniqueIndex"`
+	// AccessTokenID and AccessExpiresAt identify the access token issued with
+	// the refresh token, so it can be revoked with the session.
+	AccessTokenID   string
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time `gorm:"index"`
+	RotatedAt       *time.Time
+	RevokedAt       *time.Time
+	// AuthenticatedAt is when the user logged in to start the session, it is
+	// carried over when the token is rotated.
+	AuthenticatedAt time.Time
+	CreatedAt       time.Time
+}
+
+// RevokedToken is a revoked access token, denied until it expires. ID is the
+// jti of the token.
+type RevokedToken struct {
+	ID        string    `gorm:"primaryKey"`
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+}
+
+type UserTokenPurpose string
+
+const (
+	VerifyEmailToken    UserTokenPurpose = "verify_email"
+	ResetPasswordToken  UserTokenPurpose = "reset_password"
+	LoginChallengeToken UserTokenPurpose = "login_challenge"
+)
+
+// UserToken is a single use token mailed to a user to verify their email or
+// reset their password, or returned by a login waiting for a second factor.
+// Email is the address of the user it was issued to, and only the SHA-256 of
+// the token is stored.
+type UserToken struct {
+	ID        uint
+	UserID    uint `gorm:"index"`
+	Purpose   UserTokenPurpose
+	Email     string
+	Hash      string    `gorm:"uniqueIndex"`
+	ExpiresAt time.Time `gorm:"index"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
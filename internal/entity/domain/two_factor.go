@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// TwoFactor is the TOTP secret of a user. It is pending until the user
+// confirms a first code, and LastStep is the time step of the last code
+// used, so a code cannot be used twice.
+type TwoFactor struct {
+	UserID    uint `gorm:"primaryKey;autoIncrement:false"`
+	Secret    string
+	EnabledAt *time.Time
+	LastStep  int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// RecoveryCode signs a user in once without their authenticator. Only the
+// SHA-256 of the code is stored.
+type RecoveryCode struct {
+	ID        uint
+	UserID    uint   `gorm:"index"`
+	Hash      string `gorm:"uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
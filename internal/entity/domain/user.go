@@ -2,14 +2,29 @@ package domain
 
 import "time"
 
+type UserRole string
+
+const (
+	RegularUser   UserRole = "user"
+	ModeratorUser UserRole = "moderator"
+	AdminUser     UserRole = "admin"
+	// DeletedUser is the role of the account owning the content left behind
+	// by erased accounts. It is never given, the account is seeded by a
+	// migration.
+	DeletedUser UserRole = "deleted"
+)
+
 type User struct {
 	ID        uint
 	Name      string
 	Email     string `gorm:"unique"`
 	Password  string
+	Role      UserRole `gorm:"default:'user'"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Journals  []Journal
 	Forums    []Forum
 	Comments  []Comment
+	// VerifiedAt is when the user confirmed they own Email, nil until then.
+	VerifiedAt *time.Time
 }
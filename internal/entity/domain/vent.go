@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// VentReply records which prompt produced a vent reply. The conversation
+// itself is never stored.
+type VentReply struct {
+	ID              uint
+	UserID          uint
+	Persona         string
+	Language        string
+	TemplateVersion string
+	CreatedAt       time.Time
+}
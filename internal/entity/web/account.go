@@ -0,0 +1,61 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/auth"
+	"github.com/aternity/zense/internal/entity/domain"
+)
+
+type DataExportResponse struct {
+	ID        uint                `json:"id"`
+	Status    domain.ExportStatus `json:"status"`
+	Error     string              `json:"error,omitempty"`
+	ExpiresAt *time.Time          `json:"expires_at,omitempty"`
+	CreatedAt *time.Time          `json:"created_at,omitempty"`
+}
+
+type DataExportCreate struct {
+	auth.Principal `json:"-"`
+}
+
+type DataExportFindByID struct {
+	auth.Principal `json:"-"`
+
+	ID uint `param:"id"`
+}
+
+// AccountDeletionCreate schedules the deletion of the authenticated account.
+// The password is asked again to confirm it, and Code too when two-factor
+// authentication is enabled. Mode picks the erasure policy and defaults to
+// anonymize.
+type AccountDeletionCreate struct {
+	auth.Principal `json:"-"`
+
+	ID       uint               `param:"id"`
+	Password string             `json:"password"`
+	Code     string             `json:"code"`
+	Mode     domain.ErasureMode `json:"mode" validate:"omitempty,oneof=anonymize remove"`
+}
+
+type AccountDeletionFind struct {
+	auth.Principal `json:"-"`
+}
+
+type AccountDeletionCancel struct {
+	auth.Principal `json:"-"`
+}
+
+type AccountDeletionResponse struct {
+	Mode         domain.ErasureMode `json:"mode"`
+	ScheduledFor *time.Time         `json:"scheduled_for"`
+	CreatedAt    *time.Time         `json:"created_at,omitempty"`
+}
+
+// ReactionEntry is one reaction of a data export.
+type ReactionEntry struct {
+	TargetType domain.ReactionTarget `json:"target_type"`
+	TargetID   uint                  `json:"target_id"`
+	Type       domain.ReactionType   `json:"type"`
+	CreatedAt  time.Time             `json:"created_at"`
+}
@@ -0,0 +1,49 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/auth"
+)
+
+type AuthRefresh struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+// AuthLogout ends the session of the access token TokenID, or every session
+// of the user.
+type AuthLogout struct {
+	auth.Principal `json:"-"`
+}
+
+type AuthVerifyEmail struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type AuthResendVerification struct {
+	auth.Principal `json:"-"`
+}
+
+type AuthForgotPassword struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type AuthResetPassword struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=32"`
+}
+
+// AuthChallenge is returned by a login of a user with two-factor
+// authentication, ChallengeToken is exchanged for the session with a code.
+type AuthChallenge struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// AuthTwoFactorVerify takes a code of the authenticator or a recovery code.
+type AuthTwoFactorVerify struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+	IP             string `json:"-"`
+}
@@ -3,6 +3,7 @@ package web
 import (
 	"time"
 
+	"github.com/aternity/zense/internal/auth"
 	"github.com/aternity/zense/internal/entity/domain"
 )
 
@@ -10,32 +11,99 @@ type CommentResponse struct {
 	ID         uint                     `json:"id"`
 	ForumID    uint                     `json:"forum_id,omitempty"`
 	UserID     uint                     `json:"user_id,omitempty"`
+	ParentID   *uint                    `json:"parent_id,omitempty"`
+	Depth      int                      `json:"depth"`
 	Content    string                   `json:"content,omitempty"`
+	Deleted    bool                     `json:"deleted,omitempty"`
+	Anonymous  bool                     `json:"anonymous,omitempty"`
+	Pseudonym  string                   `json:"pseudonym,omitempty"`
 	Visibility domain.CommentVisibility `json:"comment,omitempty"`
 	CreatedAt  *time.Time               `json:"created_at,omitempty"`
 	UpdatedAt  *time.Time               `json:"updated_at,omitempty"`
+	DeletedAt  *time.Time               `json:"deleted_at,omitempty"`
 	User       *UserResponse            `json:"user,omitempty"`
+	Replies    *CommentReplies          `json:"replies,omitempty"`
+	Reactions  *ReactionSummary         `json:"reactions,omitempty"`
+}
+
+// CommentReplies is the first page of the replies to a comment, the next
+// pages are listed through the parent_id of GET /forums/{id}/comments.
+type CommentReplies struct {
+	Data       []CommentResponse `json:"data"`
+	NextCursor string            `json:"next_cursor"`
+	Total      int64             `json:"total"`
+}
+
+type CommentFindAll struct {
+	PageQuery
+	auth.Principal `json:"-"`
+
+	ForumID  uint   `query:"forum_id"`
+	AuthorID uint   `query:"user_id"`
+	Sort     string `query:"sort" validate:"omitempty,oneof=created_at updated_at"`
+}
+
+type CommentFindPending struct {
+	PageQuery
+	Sort string `query:"sort" validate:"omitempty,oneof=created_at updated_at"`
+}
+
+type CommentFindByForum struct {
+	PageQuery
+	auth.Principal `json:"-"`
+
+	ForumID    uint   `param:"id"`
+	ParentID   uint   `query:"parent_id"`
+	Mode       string `query:"mode" validate:"omitempty,oneof=tree flat"`
+	ReplyLimit int    `query:"reply_limit" validate:"omitempty,min=1,max=100"`
 }
 
 type CommentFindByID struct {
+	auth.Principal `json:"-"`
+
 	ID uint `param:"id"`
 }
 
 type CommentCreate struct {
-	UserID     uint                     `json:"user_id"`
+	auth.Principal `json:"-"`
+
 	ForumID    uint                     `json:"forum_id" validate:"required"`
+	ParentID   *uint                    `json:"parent_id"`
 	Content    string                   `validate:"required"`
 	Visibility domain.CommentVisibility `validate:"required,oneof=review public private"`
+	Anonymous  bool                     `json:"anonymous"`
 }
 
 type CommentUpdate struct {
+	auth.Principal `json:"-"`
+
 	ID         uint `param:"id"`
-	UserID     uint `json:"user_id"`
 	Content    string
 	Visibility domain.CommentVisibility `validate:"omitempty,oneof=review public private"`
 }
 
 type CommentDelete struct {
-	ID     uint `param:"id"`
-	UserID uint `json:"user_id"`
+	auth.Principal `json:"-"`
+
+	ID uint `param:"id"`
+}
+
+type CommentRestore struct {
+	auth.Principal `json:"-"`
+
+	ID uint `param:"id"`
+}
+
+type CommentApprove struct {
+	auth.Principal `json:"-"`
+
+	ID     uint   `param:"id"`
+	Reason string `json:"reason"`
+}
+
+type CommentReject struct {
+	auth.Principal `json:"-"`
+
+	ID     uint   `param:"id"`
+	Reason string `json:"reason" validate:"required"`
 }
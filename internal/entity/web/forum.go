@@ -2,45 +2,73 @@ package web
 
 import (
 	"time"
+
+	"github.com/aternity/zense/internal/auth"
 )
 
 type ForumResponse struct {
-	ID        uint            `json:"id"`
-	UserID    uint            `json:"user_id,omitempty"`
-	Title     string          `json:"title,omitempty"`
-	Content   string          `json:"content,omitempty"`
-	CreatedAt *time.Time      `json:"created_at,omitempty"`
-	UpdatedAt *time.Time      `json:"updated_at,omitempty"`
-	Topics    []TopicResponse `json:"topics,omitempty"`
-	User      *UserResponse   `json:"user,omitempty"`
+	ID        uint             `json:"id"`
+	UserID    uint             `json:"user_id,omitempty"`
+	Title     string           `json:"title,omitempty"`
+	Content   string           `json:"content,omitempty"`
+	Anonymous bool             `json:"anonymous,omitempty"`
+	Pseudonym string           `json:"pseudonym,omitempty"`
+	CreatedAt *time.Time       `json:"created_at,omitempty"`
+	UpdatedAt *time.Time       `json:"updated_at,omitempty"`
+	DeletedAt *time.Time       `json:"deleted_at,omitempty"`
+	Topics    []TopicResponse  `json:"topics,omitempty"`
+	User      *UserResponse    `json:"user,omitempty"`
+	Reactions *ReactionSummary `json:"reactions,omitempty"`
+}
+
+type ForumFindAll struct {
+	PageQuery
+	auth.Principal `json:"-"`
+
+	TopicID  uint   `query:"topic_id"`
+	AuthorID uint   `query:"user_id"`
+	Sort     string `query:"sort" validate:"omitempty,oneof=created_at updated_at title"`
 }
 
 type ForumCreate struct {
-	UserID  uint   `json:"user_id"`
-	Title   string `validate:"required"`
-	Topics  []uint `validate:"required"`
-	Content string `validate:"required"`
+	auth.Principal `json:"-"`
+
+	Title     string `validate:"required"`
+	Topics    []uint `validate:"required"`
+	Content   string `validate:"required"`
+	Anonymous bool   `json:"anonymous"`
 }
 
 type ForumFindByID struct {
+	auth.Principal `json:"-"`
+
 	ID uint `param:"id"`
 }
 
 type ForumUpdate struct {
+	auth.Principal `json:"-"`
+
 	ID      uint `param:"id"`
-	UserID  uint `json:"user_id"`
 	Title   string
 	Topics  []uint
 	Content string
 }
 
 type ForumDelete struct {
-	ID     uint `param:"id"`
-	UserID uint `json:"user_id"`
+	auth.Principal `json:"-"`
+
+	ID uint `param:"id"`
+}
+
+type ForumRestore struct {
+	auth.Principal `json:"-"`
+
+	ID uint `param:"id"`
 }
 
 type ForumRemoveTopic struct {
+	auth.Principal `json:"-"`
+
 	ID      uint `param:"id"`
-	UserID  uint `json:"user_id"`
 	TopicID uint `json:"topic_id" validate:"required"`
 }
@@ -0,0 +1,50 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/auth"
+)
+
+type OIDCProviders struct {
+	Providers []string `json:"providers"`
+}
+
+// OIDCStart starts a sign in at a provider. UserID is set when a signed in
+// user connects an identity.
+type OIDCStart struct {
+	auth.Principal `json:"-"`
+
+	Provider string `param:"provider"`
+}
+
+// OIDCStartResponse is where to send the user. The provider sends them back
+// to the redirect URL of the app with the code and the state.
+type OIDCStartResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type OIDCCallback struct {
+	auth.Principal `json:"-"`
+
+	Provider string `param:"provider"`
+	Code     string `json:"code" validate:"required"`
+	State    string `json:"state" validate:"required"`
+}
+
+type IdentityResponse struct {
+	ID        uint      `json:"id"`
+	Provider  string    `json:"provider"`
+	Email     string    `json:"email,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type IdentityFindAll struct {
+	auth.Principal `json:"-"`
+}
+
+type IdentityDelete struct {
+	auth.Principal `json:"-"`
+
+	ID uint `param:"id"`
+}
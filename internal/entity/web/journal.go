@@ -3,6 +3,7 @@ package web
 import (
 	"time"
 
+	"github.com/aternity/zense/internal/auth"
 	"github.com/aternity/zense/internal/entity/domain"
 )
 
@@ -15,28 +16,147 @@ type JournalResponse struct {
 	CreatedAt  *time.Time               `json:"created_at,omitempty"`
 	UpdatedAt  *time.Time               `json:"updated_at,omitempty"`
 	User       *UserResponse            `json:"user,omitempty"`
+	Reactions  *ReactionSummary         `json:"reactions,omitempty"`
 }
 
 type JournalCreate struct {
-	UserID     uint                     `json:"user_id"`
+	auth.Principal `json:"-"`
+
 	Mood       domain.JournalMood       `validate:"required,oneof=happy good normal sad angry"`
 	Content    string                   `validate:"required"`
 	Visibility domain.JournalVisibility `validate:"required,oneof=private public"`
 }
 
+type JournalFindAll struct {
+	PageQuery
+	JournalFilter
+	auth.Principal `json:"-"`
+}
+
+type JournalFindByUser struct {
+	PageQuery
+	JournalFilter
+	auth.Principal `json:"-"`
+
+	OwnerID uint `param:"id"`
+}
+
+// JournalFilter holds the listing filters, From and To are inclusive dates.
+type JournalFilter struct {
+	Mood domain.JournalMood `query:"mood" validate:"omitempty,oneof=happy good normal sad angry"`
+	From string             `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To   string             `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Sort string             `query:"sort" validate:"omitempty,oneof=created_at updated_at mood"`
+}
+
 type JournalFindByID struct {
+	auth.Principal `json:"-"`
+
 	ID uint `param:"id"`
 }
 
 type JournalUpdate struct {
+	auth.Principal `json:"-"`
+
 	ID         uint               `param:"id"`
-	UserID     uint               `json:"user_id"`
 	Mood       domain.JournalMood `validate:"omitempty,oneof=happy good normal sad angry"`
 	Content    string
 	Visibility domain.JournalVisibility `omitempty,validate:"oneof=private public"`
 }
 
 type JournalDelete struct {
-	ID     uint `param:"id"`
-	UserID uint `json:"user_id"`
+	auth.Principal `json:"-"`
+
+	ID uint `param:"id"`
+}
+
+type JournalRestore struct {
+	auth.Principal `json:"-"`
+
+	ID uint `param:"id"`
+}
+
+// MoodInsights asks for the mood insights of the authenticated user between
+// two inclusive dates, the last 90 days by default and 366 at most. Days start
+// at midnight in Timezone, UTC by default.
+type MoodInsights struct {
+	auth.Principal `json:"-"`
+
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Timezone string `query:"timezone"`
+}
+
+type MoodInsightsResponse struct {
+	From     string             `json:"from"`
+	To       string             `json:"to"`
+	Timezone string             `json:"timezone"`
+	Daily    []MoodDistribution `json:"daily"`
+	Weekly   []MoodDistribution `json:"weekly"`
+	Monthly  []MoodDistribution `json:"monthly"`
+	Scores   []MoodScore        `json:"scores"`
+	Streaks  MoodStreaks        `json:"streaks"`
+	Weekdays []WeekdayMood      `json:"weekdays"`
+}
+
+// MoodDistribution counts the journals of every mood in the day, week or
+// month starting on Period.
+type MoodDistribution struct {
+	Period string                       `json:"period"`
+	Counts map[domain.JournalMood]int64 `json:"counts"`
+	Total  int64                        `json:"total"`
+}
+
+// MoodScore is the average mood of a day, from 1 for angry to 5 for happy.
+type MoodScore struct {
+	Date    string  `json:"date"`
+	Score   float64 `json:"score"`
+	Entries int64   `json:"entries"`
+}
+
+// MoodStreaks counts consecutive days with a journal. The current streak is
+// still alive when the last journal was written today or yesterday.
+type MoodStreaks struct {
+	Current   int    `json:"current"`
+	Longest   int    `json:"longest"`
+	LastEntry string `json:"last_entry,omitempty"`
+}
+
+type WeekdayMood struct {
+	Weekday string             `json:"weekday"`
+	Mood    domain.JournalMood `json:"mood"`
+	Entries int64              `json:"entries"`
+}
+
+// JournalExport asks for every journal of the authenticated user. Markdown
+// exports have one section per day, days start at midnight in Timezone.
+type JournalExport struct {
+	auth.Principal `json:"-"`
+
+	Format   string `query:"format" validate:"required,oneof=json markdown csv"`
+	Timezone string `query:"timezone" validate:"omitempty,timezone"`
+}
+
+// JournalEntry is one journal of a JSON export or import. The ID of an
+// imported entry is ignored.
+type JournalEntry struct {
+	ID         uint                     `json:"id,omitempty"`
+	Mood       domain.JournalMood       `json:"mood"`
+	Visibility domain.JournalVisibility `json:"visibility"`
+	Content    string                   `json:"content"`
+	CreatedAt  time.Time                `json:"created_at"`
+	UpdatedAt  time.Time                `json:"updated_at"`
+}
+
+// JournalImport imports a JSON or CSV export into the journals of the
+// authenticated user.
+type JournalImport struct {
+	auth.Principal `json:"-"`
+
+	Format string
+}
+
+type JournalImportResponse struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
 }
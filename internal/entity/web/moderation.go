@@ -0,0 +1,21 @@
+package web
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+)
+
+type ModerationLogFindAll struct {
+	PageQuery
+}
+
+type ModerationLogResponse struct {
+	ID          uint                    `json:"id"`
+	ModeratorID uint                    `json:"moderator_id"`
+	TargetType  string                  `json:"target_type"`
+	TargetID    uint                    `json:"target_id"`
+	Action      domain.ModerationAction `json:"action"`
+	Reason      string                  `json:"reason,omitempty"`
+	CreatedAt   *time.Time              `json:"created_at,omitempty"`
+}
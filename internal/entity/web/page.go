@@ -0,0 +1,18 @@
+package web
+
+// PageQuery is embedded in every list request. Page selects offset
+// pagination, otherwise the list is walked with Cursor.
+type PageQuery struct {
+	Cursor string `query:"cursor"`
+	Page   int    `query:"page" validate:"omitempty,min=1"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Order  string `query:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+type PageResponse[T any] struct {
+	Data       []T    `json:"data"`
+	NextCursor string `json:"next_cursor"`
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	Limit      int    `json:"limit"`
+}
@@ -0,0 +1,29 @@
+package web
+
+import (
+	"github.com/aternity/zense/internal/auth"
+	"github.com/aternity/zense/internal/entity/domain"
+)
+
+// ReactionSummary aggregates the reactions left on a target, Mine lists the
+// ones left by the viewer.
+type ReactionSummary struct {
+	Counts map[domain.ReactionType]int64 `json:"counts"`
+	Mine   []domain.ReactionType         `json:"mine"`
+}
+
+type ReactionCreate struct {
+	auth.Principal `json:"-"`
+
+	TargetType domain.ReactionTarget `json:"-"`
+	TargetID   uint                  `param:"id"`
+	Type       domain.ReactionType   `param:"type" validate:"required,oneof=hug relate thanks strength hope"`
+}
+
+type ReactionDelete struct {
+	auth.Principal `json:"-"`
+
+	TargetType domain.ReactionTarget `json:"-"`
+	TargetID   uint                  `param:"id"`
+	Type       domain.ReactionType   `param:"type" validate:"required,oneof=hug relate thanks strength hope"`
+}
@@ -0,0 +1,22 @@
+package web
+
+import "time"
+
+type SearchResponse struct {
+	Type      string     `json:"type"`
+	ID        uint       `json:"id"`
+	Title     string     `json:"title,omitempty"`
+	Snippet   string     `json:"snippet"`
+	Rank      float64    `json:"rank"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// Search results are ranked, so they are paged with page and limit only.
+type Search struct {
+	Query    string `query:"q" validate:"required,max=200"`
+	Language string `query:"language" validate:"omitempty,oneof=english indonesian"`
+	Type     string `query:"type" validate:"omitempty,oneof=forum journal topic"`
+	TopicID  uint   `query:"topic_id"`
+	Page     int    `query:"page" validate:"omitempty,min=1"`
+	Limit    int    `query:"limit" validate:"omitempty,min=1,max=100"`
+}
@@ -16,6 +16,12 @@ type TopicCreate struct {
 	Description string `validate:"required"`
 }
 
+type TopicFindAll struct {
+	PageQuery
+	Name string `query:"name"`
+	Sort string `query:"sort" validate:"omitempty,oneof=created_at name"`
+}
+
 type TopicFindByID struct {
 	ID uint `param:"id"`
 }
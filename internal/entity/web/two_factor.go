@@ -0,0 +1,39 @@
+package web
+
+import "github.com/aternity/zense/internal/auth"
+
+type TwoFactorSetup struct {
+	auth.Principal `json:"-"`
+}
+
+// TwoFactorSetupResponse is a pending secret, URI is shown as a QR code for
+// authenticator apps to scan.
+type TwoFactorSetupResponse struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type TwoFactorEnable struct {
+	auth.Principal `json:"-"`
+
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorDisable struct {
+	auth.Principal `json:"-"`
+
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type TwoFactorRecoveryCodes struct {
+	auth.Principal `json:"-"`
+
+	Code string `json:"code" validate:"required"`
+}
+
+// TwoFactorRecoveryCodesResponse is shown once, only the hashes of the codes
+// are kept.
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
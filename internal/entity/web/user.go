@@ -1,19 +1,32 @@
 package web
 
-import "time"
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/auth"
+	"github.com/aternity/zense/internal/entity/domain"
+)
 
 type UserResponse struct {
-	ID        uint       `json:"id"`
-	Name      string     `json:"name,omitempty"`
-	Email     string     `json:"email,omitempty"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	ID         uint            `json:"id"`
+	Name       string          `json:"name,omitempty"`
+	Email      string          `json:"email,omitempty"`
+	Role       domain.UserRole `json:"role,omitempty"`
+	VerifiedAt *time.Time      `json:"verified_at,omitempty"`
+	CreatedAt  *time.Time      `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time      `json:"updated_at,omitempty"`
 }
 
+// UserAuth is a new session: a short lived access token and the refresh
+// token that renews it.
 type UserAuth struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Token string `json:"token"`
+	ID           uint            `json:"id"`
+	Name         string          `json:"name"`
+	Role         domain.UserRole `json:"role"`
+	Verified     bool            `json:"verified"`
+	Token        string          `json:"token"`
+	ExpiresAt    *time.Time      `json:"expires_at"`
+	RefreshToken string          `json:"refresh_token"`
 }
 
 type UserRegister struct {
@@ -25,10 +38,17 @@ type UserRegister struct {
 type UserLogin struct {
 	Email    string `validate:"required,email"`
 	Password string `validate:"required"`
+	IP       string `json:"-"`
 }
 
 type UserFindMe struct {
-	ID uint
+	auth.Principal `json:"-"`
+}
+
+type UserFindAll struct {
+	PageQuery
+	Name string `query:"name"`
+	Sort string `query:"sort" validate:"omitempty,oneof=created_at name"`
 }
 
 type UserFindByID struct {
@@ -36,14 +56,28 @@ type UserFindByID struct {
 }
 
 type UserUpdate struct {
+	auth.Principal `json:"-"`
+
 	ID       uint   `param:"id"`
-	UserID   uint   `json:"user_id"`
 	Name     string `validate:"max=16"`
 	Email    string `validate:"omitempty,email"`
 	Password string `validate:"max=32"`
+	// CurrentPassword, and Code when two-factor authentication is enabled,
+	// confirm a change of email or password.
+	CurrentPassword string `json:"current_password"`
+	Code            string `json:"code"`
+}
+
+// UserUpdateRole gives the user ID the role Role, the principal is the admin
+// giving it.
+type UserUpdateRole struct {
+	auth.Principal `json:"-"`
+
+	ID   uint            `param:"id"`
+	Role domain.UserRole `validate:"required,oneof=user moderator admin"`
 }
 
-type UserDelete struct {
-	ID     uint `param:"id"`
-	UserID uint `json:"user_id"`
+type UserBootstrapAdmin struct {
+	Email    string
+	Password string
 }
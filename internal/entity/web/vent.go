@@ -1,9 +1,35 @@
 package web
 
+import (
+	"github.com/aternity/zense/internal/auth"
+	"github.com/aternity/zense/internal/safety"
+)
+
 type VentResponse struct {
-	Message string `json:"message"`
+	Message         string      `json:"message"`
+	Persona         string      `json:"persona,omitempty"`
+	TemplateVersion string      `json:"template_version,omitempty"`
+	Safety          *VentSafety `json:"safety,omitempty"`
+}
+
+type VentSafety struct {
+	Level     safety.Level      `json:"level"`
+	Category  safety.Category   `json:"category"`
+	Resources []safety.Resource `json:"resources"`
 }
 
 type VentRequest struct {
-	Message string `json:"message" validate:"required"`
+	auth.Principal `json:"-"`
+
+	Message  string `json:"message" validate:"required"`
+	Persona  string `json:"persona" validate:"omitempty,max=32" example:"listener"`
+	Language string `json:"language" validate:"omitempty,oneof=id en"`
+}
+
+type VentClear struct {
+	auth.Principal `json:"-"`
+}
+
+type VentChunk struct {
+	Delta string `json:"delta"`
 }
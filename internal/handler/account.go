@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type AccountHandler interface {
+	CreateExport(ctx echo.Context) error
+	FindExport(ctx echo.Context) error
+	DownloadExport(ctx echo.Context) error
+	ScheduleDeletion(ctx echo.Context) error
+	FindDeletion(ctx echo.Context) error
+	CancelDeletion(ctx echo.Context) error
+}
+
+type accountHandler struct {
+	accountService service.AccountService
+	validator      *validator.Validate
+}
+
+func NewAccountHandler(accountService service.AccountService, validator *validator.Validate) AccountHandler {
+	return &accountHandler{
+		accountService: accountService,
+		validator:      validator,
+	}
+}
+
+// @Summary		Request a data export
+// @Description	Queue a ZIP archive of the profile, journals, forums, comments and reactions of the authenticated user. The export already waiting in the queue is returned when there is one.
+// @Tags			Account
+// @Produce		json
+// @Success		202	{object}	web.DataExportResponse
+// @Security		BearerAuth
+// @Router			/users/me/exports [post]
+func (h *accountHandler) CreateExport(ctx echo.Context) error {
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := web.DataExportCreate{
+		Principal: principal,
+	}
+
+	data, err := h.accountService.CreateExport(req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusAccepted, data)
+}
+
+// @Summary		Get a data export
+// @Description	Get the status of a data export of the authenticated user
+// @Tags			Account
+// @Produce		json
+// @Param			id	path		int	true	"Export ID"
+// @Success		200	{object}	web.DataExportResponse
+// @Security		BearerAuth
+// @Router			/users/me/exports/{id} [get]
+func (h *accountHandler) FindExport(ctx echo.Context) error {
+	req := new(web.DataExportFindByID)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	data, err := h.accountService.FindExport(*req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "export not found")
+		}
+
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Download a data export
+// @Description	Download the ZIP archive of a ready data export of the authenticated user
+// @Tags			Account
+// @Produce		application/zip
+// @Param			id	path	int	true	"Export ID"
+// @Success		200	{file}	file
+// @Security		BearerAuth
+// @Router			/users/me/exports/{id}/download [get]
+func (h *accountHandler) DownloadExport(ctx echo.Context) error {
+	req := new(web.DataExportFindByID)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	file, err := h.accountService.DownloadExport(*req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "export not found")
+		}
+
+		return err
+	}
+
+	return ctx.Attachment(file, "zense-export.zip")
+}
+
+// @Summary		Delete a user
+// @Description	Schedule the deletion of the authenticated user after confirming their password, and their two-factor code when it is enabled. Accounts without a password must have logged in within the re-authentication window. The account is erased once the grace period is over, unless the deletion is cancelled first. The anonymize mode keeps forums and comments under a "Deleted user" account, the remove mode erases their text too.
+// @Tags			Users
+// @Accept			json
+// @Produce		json
+// @Param			id			path		int							true	"User ID"
+// @Param			deletion	body		web.AccountDeletionCreate	true	"Password and erasure mode"
+// @Success		202			{object}	web.AccountDeletionResponse
+// @Security		BearerAuth
+// @Router			/users/{id} [delete]
+func (h *accountHandler) ScheduleDeletion(ctx echo.Context) error {
+	req := new(web.AccountDeletionCreate)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.accountService.ScheduleDeletion(*req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "user not found")
+		}
+
+		return err
+	}
+
+	return ctx.JSON(http.StatusAccepted, data)
+}
+
+// @Summary		Get the scheduled deletion
+// @Description	Get the deletion scheduled for the authenticated user
+// @Tags			Account
+// @Produce		json
+// @Success		200	{object}	web.AccountDeletionResponse
+// @Security		BearerAuth
+// @Router			/users/me/deletion [get]
+func (h *accountHandler) FindDeletion(ctx echo.Context) error {
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := web.AccountDeletionFind{
+		Principal: principal,
+	}
+
+	data, err := h.accountService.FindDeletion(req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "no deletion is scheduled")
+		}
+
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Cancel the scheduled deletion
+// @Description	Cancel the deletion scheduled for the authenticated user during the grace period
+// @Tags			Account
+// @Success		204
+// @Security		BearerAuth
+// @Router			/users/me/deletion [delete]
+func (h *accountHandler) CancelDeletion(ctx echo.Context) error {
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := web.AccountDeletionCancel{
+		Principal: principal,
+	}
+
+	if err := h.accountService.CancelDeletion(req); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "no deletion is scheduled")
+		}
+
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,396 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/aternity/zense/internal/auth"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/lockout"
+	"github.com/aternity/zense/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type AuthHandler interface {
+	Register(ctx echo.Context) error
+	Login(ctx echo.Context) error
+	VerifyTwoFactor(ctx echo.Context) error
+	OIDCProviders(ctx echo.Context) error
+	StartOIDC(ctx echo.Context) error
+	LoginOIDC(ctx echo.Context) error
+	Refresh(ctx echo.Context) error
+	Logout(ctx echo.Context) error
+	LogoutAll(ctx echo.Context) error
+	VerifyEmail(ctx echo.Context) error
+	ResendVerification(ctx echo.Context) error
+	ForgotPassword(ctx echo.Context) error
+	ResetPassword(ctx echo.Context) error
+}
+
+type authHandler struct {
+	authService service.AuthService
+	validator   *validator.Validate
+}
+
+func NewAuthHandler(authService service.AuthService, validator *validator.Validate) AuthHandler {
+	return &authHandler{
+		authService: authService,
+		validator:   validator,
+	}
+}
+
+// @Summary		Register a new user
+// @Description	Register a new user with email and password, and mail them a link to verify the email. Unverified users cannot post forums or comments.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			user	body		web.UserRegister	true	"User Register Request"
+// @Success		201		{object}	web.UserResponse
+// @Router			/auth/register [post]
+func (h *authHandler) Register(ctx echo.Context) error {
+	req := new(web.UserRegister)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.authService.Register(*req)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			if pgErr.Code == "23505" {
+				return echo.NewHTTPError(http.StatusConflict, "email already registered")
+			}
+		}
+
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, data)
+}
+
+// @Summary		User login
+// @Description	Authenticate a user and start a session with a short lived access token and a refresh token. Repeated failures lock the account and the IP address out for a while. Users with two-factor authentication get a web.AuthChallenge to verify instead.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			user	body		web.UserLogin	true	"User Login Request"
+// @Success		200		{object}	web.UserAuth
+// @Router			/auth/login [post]
+func (h *authHandler) Login(ctx echo.Context) error {
+	req := new(web.UserLogin)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.IP = ctx.RealIP()
+
+	data, challenge, err := h.authService.Login(*req)
+	if err != nil {
+		return lockedOut(ctx, err)
+	}
+
+	if challenge != nil {
+		return ctx.JSON(http.StatusOK, challenge)
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Verify the second factor
+// @Description	Exchange the challenge token of a login with two-factor authentication and a code of the authenticator, or a recovery code, for the session. A challenge can be tried once.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			verify	body		web.AuthTwoFactorVerify	true	"Challenge token and code"
+// @Success		200		{object}	web.UserAuth
+// @Router			/auth/2fa/verify [post]
+func (h *authHandler) VerifyTwoFactor(ctx echo.Context) error {
+	req := new(web.AuthTwoFactorVerify)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.IP = ctx.RealIP()
+
+	data, err := h.authService.VerifyTwoFactor(*req)
+	if err != nil {
+		return lockedOut(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Refresh the access token
+// @Description	Exchange a refresh token for a new access token and a new refresh token. Every refresh token can be used once, using one again revokes the session.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			refresh	body		web.AuthRefresh	true	"Refresh token"
+// @Success		200		{object}	web.UserAuth
+// @Router			/auth/refresh [post]
+func (h *authHandler) Refresh(ctx echo.Context) error {
+	req := new(web.AuthRefresh)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.authService.Refresh(*req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Log out
+// @Description	Revoke the access token and the session it belongs to
+// @Tags			Auth
+// @Success		204
+// @Security		BearerAuth
+// @Router			/auth/logout [post]
+func (h *authHandler) Logout(ctx echo.Context) error {
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := web.AuthLogout{
+		Principal: principal,
+	}
+
+	if err := h.authService.Logout(req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Summary		Log out all devices
+// @Description	Revoke every session of the authenticated user and their access tokens
+// @Tags			Auth
+// @Success		204
+// @Security		BearerAuth
+// @Router			/auth/logout-all [post]
+func (h *authHandler) LogoutAll(ctx echo.Context) error {
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := web.AuthLogout{
+		Principal: principal,
+	}
+
+	if err := h.authService.LogoutAll(req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Summary		Verify the email
+// @Description	Verify the email of a user with the token of the link mailed to them. Access tokens issued before carry the old status until they are refreshed.
+// @Tags			Auth
+// @Accept			json
+// @Param			token	body	web.AuthVerifyEmail	true	"Verification token"
+// @Success		204
+// @Router			/auth/verify-email [post]
+func (h *authHandler) VerifyEmail(ctx echo.Context) error {
+	req := new(web.AuthVerifyEmail)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authService.VerifyEmail(*req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Summary		Resend the verification email
+// @Description	Mail the authenticated user a new link to verify their email, the previous links stop working
+// @Tags			Auth
+// @Success		202
+// @Security		BearerAuth
+// @Router			/auth/verify-email/resend [post]
+func (h *authHandler) ResendVerification(ctx echo.Context) error {
+	req := new(web.AuthResendVerification)
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.authService.ResendVerification(*req); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "user not found")
+		}
+
+		return err
+	}
+
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+// @Summary		Forgot password
+// @Description	Mail a link to reset the password. The response is the same whether the email is registered or not.
+// @Tags			Auth
+// @Accept			json
+// @Param			email	body	web.AuthForgotPassword	true	"Email of the account"
+// @Success		202
+// @Router			/auth/forgot-password [post]
+func (h *authHandler) ForgotPassword(ctx echo.Context) error {
+	req := new(web.AuthForgotPassword)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authService.ForgotPassword(*req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusAccepted)
+}
+
+// @Summary		Reset password
+// @Description	Set a new password with the token of the link mailed by forgot password. Every session of the user is revoked.
+// @Tags			Auth
+// @Accept			json
+// @Param			reset	body	web.AuthResetPassword	true	"Reset token and new password"
+// @Success		204
+// @Router			/auth/reset-password [post]
+func (h *authHandler) ResetPassword(ctx echo.Context) error {
+	req := new(web.AuthResetPassword)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.authService.ResetPassword(*req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Summary		List the OIDC providers
+// @Description	List the names of the OpenID Connect providers users can sign in with
+// @Tags			Auth
+// @Produce		json
+// @Success		200	{object}	web.OIDCProviders
+// @Router			/auth/oidc [get]
+func (h *authHandler) OIDCProviders(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, h.authService.OIDCProviders())
+}
+
+// @Summary		Start signing in with a provider
+// @Description	Start the authorization code flow with PKCE at an OpenID Connect provider. Send the user to authorization_url, the provider sends them back to the app with the code and the state to post to the callback.
+// @Tags			Auth
+// @Produce		json
+// @Param			provider	path		string	true	"Provider name"
+// @Success		200			{object}	web.OIDCStartResponse
+// @Router			/auth/oidc/{provider} [post]
+func (h *authHandler) StartOIDC(ctx echo.Context) error {
+	req := new(web.OIDCStart)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.authService.StartOIDC(ctx.Request().Context(), *req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Finish signing in with a provider
+// @Description	Exchange the code and the state the provider returned for a session. The first sign in links the account with the same verified email, or creates one. Users with two-factor authentication get a web.AuthChallenge to verify instead.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			provider	path		string				true	"Provider name"
+// @Param			callback	body		web.OIDCCallback	true	"Code and state"
+// @Success		200			{object}	web.UserAuth
+// @Router			/auth/oidc/{provider}/callback [post]
+func (h *authHandler) LoginOIDC(ctx echo.Context) error {
+	req := new(web.OIDCCallback)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, challenge, err := h.authService.LoginOIDC(ctx.Request().Context(), *req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "user not found")
+		}
+
+		return err
+	}
+
+	if challenge != nil {
+		return ctx.JSON(http.StatusOK, challenge)
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// lockedOut turns the error of a locked out login into 429 with the time to
+// wait.
+func lockedOut(ctx echo.Context, err error) error {
+	var locked *lockout.LockedError
+	if errors.As(err, &locked) {
+		ctx.Response().Header().Set("Retry-After", strconv.Itoa(int(locked.RetryAfter.Seconds())+1))
+		return echo.NewHTTPError(http.StatusTooManyRequests, "too many failed login attempts, try again later")
+	}
+
+	return err
+}
+
+// requirePrincipal returns the principal the auth middleware put in the
+// context of the request, 401 when there is none.
+func requirePrincipal(ctx echo.Context) (auth.Principal, error) {
+	principal, ok := auth.FromContext(ctx.Request().Context())
+	if !ok {
+		return auth.Principal{}, echo.NewHTTPError(http.StatusUnauthorized, "missing or malformed jwt")
+	}
+
+	return principal, nil
+}
@@ -4,10 +4,10 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/aternity/zense/internal/auth"
 	"github.com/aternity/zense/internal/entity/web"
 	"github.com/aternity/zense/internal/service"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
@@ -15,9 +15,11 @@ import (
 type CommentHandler interface {
 	Create(ctx echo.Context) error
 	FindAll(ctx echo.Context) error
+	FindByForum(ctx echo.Context) error
 	FindByID(ctx echo.Context) error
 	Update(ctx echo.Context) error
 	Delete(ctx echo.Context) error
+	Restore(ctx echo.Context) error
 }
 
 type commentHandler struct {
@@ -47,9 +49,11 @@ func (h *commentHandler) Create(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -64,18 +68,58 @@ func (h *commentHandler) Create(ctx echo.Context) error {
 }
 
 // @Summary		Get all comments
-// @Description	Retrieve all comments
+// @Description	Retrieve all approved public comments, plus every comment of the authenticated user
 // @Tags			Comments
-// @Accept			json
 // @Produce		json
-// @Success		200	{array}	web.CommentResponse
-// @Security		Bearer
+// @Param			query	query		web.CommentFindAll	false	"Pagination, sorting and filters"
+// @Success		200		{object}	web.PageResponse[web.CommentResponse]
+// @Security		BearerAuth
 // @Router			/comments [get]
 func (h *commentHandler) FindAll(ctx echo.Context) error {
-	data, err := h.commentService.FindAll()
+	req := new(web.CommentFindAll)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Principal, _ = auth.FromContext(ctx.Request().Context())
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.commentService.FindAll(*req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Get the comments of a forum
+// @Description	Retrieve the thread of a forum, as a tree with the first replies of every comment embedded or as a flat chronological list
+// @Tags			Comments
+// @Produce		json
+// @Param			id		path		int						true	"Forum ID"
+// @Param			query	query		web.CommentFindByForum	false	"Pagination, thread mode and parent comment"
+// @Success		200		{object}	web.PageResponse[web.CommentResponse]
+// @Security		BearerAuth
+// @Router			/forums/{id}/comments [get]
+func (h *commentHandler) FindByForum(ctx echo.Context) error {
+	req := new(web.CommentFindByForum)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Principal, _ = auth.FromContext(ctx.Request().Context())
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.commentService.FindByForum(*req)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "comments not found")
+			return echo.NewHTTPError(http.StatusNotFound, "forum not found")
 		}
 
 		return err
@@ -91,6 +135,7 @@ func (h *commentHandler) FindAll(ctx echo.Context) error {
 // @Produce		json
 // @Param			id	path		int	true	"Comment ID"
 // @Success		200	{object}	web.CommentResponse
+// @Security		BearerAuth
 // @Router			/comments/{id} [get]
 func (h *commentHandler) FindByID(ctx echo.Context) error {
 	req := new(web.CommentFindByID)
@@ -98,6 +143,8 @@ func (h *commentHandler) FindByID(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	req.Principal, _ = auth.FromContext(ctx.Request().Context())
+
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -130,9 +177,11 @@ func (h *commentHandler) Update(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -165,9 +214,11 @@ func (h *commentHandler) Delete(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -183,3 +234,35 @@ func (h *commentHandler) Delete(ctx echo.Context) error {
 
 	return ctx.NoContent(http.StatusNoContent)
 }
+
+// @Summary		Restore a comment
+// @Description	Restore a deleted comment or the placeholder it left behind. Authors restore their own comments, moderators restore any comment.
+// @Tags			Comments
+// @Produce		json
+// @Param			id	path		int	true	"Comment ID"
+// @Success		200	{object}	web.CommentResponse
+// @Security		BearerAuth
+// @Router			/comments/{id}/restore [post]
+func (h *commentHandler) Restore(ctx echo.Context) error {
+	req := new(web.CommentRestore)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	data, err := h.commentService.Restore(*req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "deleted comment not found")
+		}
+
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
@@ -4,10 +4,10 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/aternity/zense/internal/auth"
 	"github.com/aternity/zense/internal/entity/web"
 	"github.com/aternity/zense/internal/service"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
@@ -18,6 +18,7 @@ type ForumHandler interface {
 	FindByID(ctx echo.Context) error
 	Update(ctx echo.Context) error
 	Delete(ctx echo.Context) error
+	Restore(ctx echo.Context) error
 	RemoveTopic(ctx echo.Context) error
 }
 
@@ -48,9 +49,11 @@ func (h *forumHandler) Create(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -65,18 +68,27 @@ func (h *forumHandler) Create(ctx echo.Context) error {
 }
 
 // @Summary		Get All Forums
-// @Description	Get all forum posts
+// @Description	Get forum posts, filtered by topic or author
 // @Tags			Forums
 // @Produce		json
-// @Success		200	{array}	web.ForumResponse
+// @Param			query	query		web.ForumFindAll	false	"Pagination, sorting and filters"
+// @Success		200		{object}	web.PageResponse[web.ForumResponse]
+// @Security		BearerAuth
 // @Router			/forums [get]
 func (h *forumHandler) FindAll(ctx echo.Context) error {
-	data, err := h.forumService.FindAll()
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "forums not found")
-		}
+	req := new(web.ForumFindAll)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 
+	req.Principal, _ = auth.FromContext(ctx.Request().Context())
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.forumService.FindAll(*req)
+	if err != nil {
 		return err
 	}
 
@@ -90,6 +102,7 @@ func (h *forumHandler) FindAll(ctx echo.Context) error {
 // @Produce		json
 // @Param			id	path		string	true	"Forum ID"
 // @Success		200	{object}	web.ForumResponse
+// @Security		BearerAuth
 // @Router			/forums/{id} [get]
 func (h *forumHandler) FindByID(ctx echo.Context) error {
 	req := new(web.ForumFindByID)
@@ -97,6 +110,8 @@ func (h *forumHandler) FindByID(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	req.Principal, _ = auth.FromContext(ctx.Request().Context())
+
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -129,9 +144,11 @@ func (h *forumHandler) Update(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -163,9 +180,11 @@ func (h *forumHandler) Delete(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -182,6 +201,38 @@ func (h *forumHandler) Delete(ctx echo.Context) error {
 	return ctx.NoContent(http.StatusNoContent)
 }
 
+// @Summary		Restore Forum
+// @Description	Restore a deleted forum with the comments deleted along with it. Authors restore their own forums, moderators restore any forum.
+// @Tags			Forums
+// @Produce		json
+// @Param			id	path		int	true	"Forum ID"
+// @Success		200	{object}	web.ForumResponse
+// @Security		BearerAuth
+// @Router			/forums/{id}/restore [post]
+func (h *forumHandler) Restore(ctx echo.Context) error {
+	req := new(web.ForumRestore)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	data, err := h.forumService.Restore(*req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "deleted forum not found")
+		}
+
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
 // @Summary		Remove Topic from Forum
 // @Description	Remove a topic from a specific forum by its ID
 // @Tags			Forums
@@ -197,9 +248,11 @@ func (h *forumHandler) RemoveTopic(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
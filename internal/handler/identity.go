@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type IdentityHandler interface {
+	FindAll(ctx echo.Context) error
+	Connect(ctx echo.Context) error
+	ConnectCallback(ctx echo.Context) error
+	Delete(ctx echo.Context) error
+}
+
+type identityHandler struct {
+	identityService service.IdentityService
+	validator       *validator.Validate
+}
+
+func NewIdentityHandler(identityService service.IdentityService, validator *validator.Validate) IdentityHandler {
+	return &identityHandler{
+		identityService: identityService,
+		validator:       validator,
+	}
+}
+
+// @Summary		List connected identities
+// @Description	List the OpenID Connect identities connected to the authenticated user
+// @Tags			Users
+// @Produce		json
+// @Success		200	{array}	web.IdentityResponse
+// @Security		BearerAuth
+// @Router			/users/me/identities [get]
+func (h *identityHandler) FindAll(ctx echo.Context) error {
+	req := new(web.IdentityFindAll)
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	data, err := h.identityService.FindAll(*req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Connect an identity
+// @Description	Start signing in at an OpenID Connect provider to connect it to the authenticated user. Post the code and the state the provider returns to the connect callback.
+// @Tags			Users
+// @Produce		json
+// @Param			provider	path		string	true	"Provider name"
+// @Success		200			{object}	web.OIDCStartResponse
+// @Security		BearerAuth
+// @Router			/users/me/identities/{provider} [post]
+func (h *identityHandler) Connect(ctx echo.Context) error {
+	req := new(web.OIDCStart)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	data, err := h.identityService.Connect(ctx.Request().Context(), *req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Finish connecting an identity
+// @Description	Connect the identity the authenticated user signed in with at the provider
+// @Tags			Users
+// @Accept			json
+// @Produce		json
+// @Param			provider	path		string				true	"Provider name"
+// @Param			callback	body		web.OIDCCallback	true	"Code and state"
+// @Success		201			{object}	web.IdentityResponse
+// @Security		BearerAuth
+// @Router			/users/me/identities/{provider}/callback [post]
+func (h *identityHandler) ConnectCallback(ctx echo.Context) error {
+	req := new(web.OIDCCallback)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.identityService.ConnectCallback(ctx.Request().Context(), *req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, data)
+}
+
+// @Summary		Disconnect an identity
+// @Description	Disconnect an identity from the authenticated user. Users without a password cannot disconnect their last identity.
+// @Tags			Users
+// @Param			id	path	int	true	"Identity ID"
+// @Success		204
+// @Security		BearerAuth
+// @Router			/users/me/identities/{id} [delete]
+func (h *identityHandler) Delete(ctx echo.Context) error {
+	req := new(web.IdentityDelete)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.identityService.Delete(*req); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "identity not found")
+		}
+
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -2,12 +2,14 @@ package handler
 
 import (
 	"errors"
+	"fmt"
+	"mime"
 	"net/http"
 
+	"github.com/aternity/zense/internal/auth"
 	"github.com/aternity/zense/internal/entity/web"
 	"github.com/aternity/zense/internal/service"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
@@ -15,9 +17,25 @@ import (
 type JournalHandler interface {
 	Create(ctx echo.Context) error
 	FindAll(ctx echo.Context) error
+	FindByUser(ctx echo.Context) error
 	FindByID(ctx echo.Context) error
 	Update(ctx echo.Context) error
 	Delete(ctx echo.Context) error
+	Restore(ctx echo.Context) error
+	MoodInsights(ctx echo.Context) error
+	Export(ctx echo.Context) error
+	Import(ctx echo.Context) error
+}
+
+// journalExports holds the content type and file extension of every export
+// format.
+var journalExports = map[string]struct {
+	contentType string
+	extension   string
+}{
+	"json":     {echo.MIMEApplicationJSONCharsetUTF8, "json"},
+	"csv":      {"text/csv; charset=utf-8", "csv"},
+	"markdown": {"text/markdown; charset=utf-8", "md"},
 }
 
 type journalHandler struct {
@@ -47,9 +65,11 @@ func (h *journalHandler) Create(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -64,18 +84,55 @@ func (h *journalHandler) Create(ctx echo.Context) error {
 }
 
 // @Summary		Get All Journals
-// @Description	Get all journals
+// @Description	Get all public journals, plus the private journals of the authenticated user
 // @Tags			Journals
 // @Produce		json
-// @Success		200	{array}	web.JournalResponse
+// @Param			query	query		web.JournalFindAll	false	"Pagination, sorting and filters"
+// @Success		200		{object}	web.PageResponse[web.JournalResponse]
+// @Security		BearerAuth
 // @Router			/journals [get]
 func (h *journalHandler) FindAll(ctx echo.Context) error {
-	data, err := h.journalService.FindAll()
+	req := new(web.JournalFindAll)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	req.Principal, _ = auth.FromContext(ctx.Request().Context())
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.journalService.FindAll(*req)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "journals not found")
-		}
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Get Journals by User
+// @Description	Get the public journals of a user, or all of them when the user is the authenticated user
+// @Tags			Journals
+// @Produce		json
+// @Param			query	query		web.JournalFindByUser	false	"Pagination, sorting and filters"
+// @Success		200		{object}	web.PageResponse[web.JournalResponse]
+// @Security		BearerAuth
+// @Router			/users/{id}/journals [get]
+func (h *journalHandler) FindByUser(ctx echo.Context) error {
+	req := new(web.JournalFindByUser)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 
+	req.Principal, _ = auth.FromContext(ctx.Request().Context())
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.journalService.FindByUser(*req)
+	if err != nil {
 		return err
 	}
 
@@ -89,6 +146,7 @@ func (h *journalHandler) FindAll(ctx echo.Context) error {
 // @Produce		json
 // @Param			id	path		int	true	"Journal ID"
 // @Success		200	{object}	web.JournalResponse
+// @Security		BearerAuth
 // @Router			/journals/{id} [get]
 func (h *journalHandler) FindByID(ctx echo.Context) error {
 	req := new(web.JournalFindByID)
@@ -96,6 +154,8 @@ func (h *journalHandler) FindByID(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	req.Principal, _ = auth.FromContext(ctx.Request().Context())
+
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
@@ -128,9 +188,11 @@ func (h *journalHandler) Update(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -162,9 +224,11 @@ func (h *journalHandler) Delete(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -180,3 +244,143 @@ func (h *journalHandler) Delete(ctx echo.Context) error {
 
 	return ctx.NoContent(http.StatusNoContent)
 }
+
+// @Summary		Restore Journal
+// @Description	Restore a deleted journal. Authors restore their own journals, moderators restore any public journal.
+// @Tags			Journals
+// @Produce		json
+// @Param			id	path		int	true	"Journal ID"
+// @Success		200	{object}	web.JournalResponse
+// @Security		BearerAuth
+// @Router			/journals/{id}/restore [post]
+func (h *journalHandler) Restore(ctx echo.Context) error {
+	req := new(web.JournalRestore)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.journalService.Restore(*req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "deleted journal not found")
+		}
+
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Get Mood Insights
+// @Description	Get the mood distributions per day, week and month, the daily mood score, the journaling streaks and the most frequent mood by weekday of the authenticated user
+// @Tags			Journals
+// @Produce		json
+// @Param			query	query		web.MoodInsights	false	"Date range and timezone"
+// @Success		200		{object}	web.MoodInsightsResponse
+// @Security		BearerAuth
+// @Router			/users/me/mood-insights [get]
+func (h *journalHandler) MoodInsights(ctx echo.Context) error {
+	req := new(web.MoodInsights)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.journalService.MoodInsights(*req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Export Journals
+// @Description	Download every journal of the authenticated user as JSON, CSV or Markdown with one section per day
+// @Tags			Journals
+// @Produce		json
+// @Produce		text/csv
+// @Produce		text/markdown
+// @Param			query	query	web.JournalExport	true	"Format and timezone of the Markdown days"
+// @Success		200		{array}	web.JournalEntry
+// @Security		BearerAuth
+// @Router			/users/me/journals/export [get]
+func (h *journalHandler) Export(ctx echo.Context) error {
+	req := new(web.JournalExport)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	export := journalExports[req.Format]
+	ctx.Response().Header().Set(echo.HeaderContentType, export.contentType)
+	ctx.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="journals.%s"`, export.extension))
+	ctx.Response().WriteHeader(http.StatusOK)
+
+	return h.journalService.Export(*req, ctx.Response())
+}
+
+// @Summary		Import Journals
+// @Description	Import a JSON or CSV journal export, picked by the Content-Type of the body. Every entry is validated before any is created, and entries with the same creation time and content as an existing journal are skipped, so importing a file twice is a no-op.
+// @Tags			Journals
+// @Accept			json
+// @Accept			text/csv
+// @Produce		json
+// @Param			journals	body		[]web.JournalEntry	true	"Journal export"
+// @Success		200			{object}	web.JournalImportResponse
+// @Security		BearerAuth
+// @Router			/users/me/journals/import [post]
+func (h *journalHandler) Import(ctx echo.Context) error {
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+
+	req := web.JournalImport{
+		Principal: principal,
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(ctx.Request().Header.Get(echo.HeaderContentType))
+	switch mediaType {
+	case echo.MIMEApplicationJSON:
+		req.Format = "json"
+	case "text/csv":
+		req.Format = "csv"
+	default:
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "journals can be imported from application/json or text/csv")
+	}
+
+	data, err := h.journalService.Import(req, ctx.Request().Body)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
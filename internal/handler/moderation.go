@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ModerationHandler interface {
+	FindPendingComments(ctx echo.Context) error
+	ApproveComment(ctx echo.Context) error
+	RejectComment(ctx echo.Context) error
+	FindLogs(ctx echo.Context) error
+}
+
+type moderationHandler struct {
+	moderationService service.ModerationService
+	validator         *validator.Validate
+}
+
+func NewModerationHandler(moderationService service.ModerationService, validator *validator.Validate) ModerationHandler {
+	return &moderationHandler{
+		moderationService: moderationService,
+		validator:         validator,
+	}
+}
+
+// @Summary		Get pending comments
+// @Description	Retrieve the comments waiting for review, oldest first
+// @Tags			Moderation
+// @Produce		json
+// @Param			query	query		web.CommentFindPending	false	"Pagination, sorting and filters"
+// @Success		200		{object}	web.PageResponse[web.CommentResponse]
+// @Security		BearerAuth
+// @Router			/moderation/comments [get]
+func (h *moderationHandler) FindPendingComments(ctx echo.Context) error {
+	req := new(web.CommentFindPending)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.moderationService.FindPendingComments(*req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Approve a comment
+// @Description	Publish a comment that is waiting for review
+// @Tags			Moderation
+// @Accept			json
+// @Param			id		path	int					true	"Comment ID"
+// @Param			comment	body	web.CommentApprove	false	"Approval Data"
+// @Success		204
+// @Security		BearerAuth
+// @Router			/moderation/comments/{id}/approve [post]
+func (h *moderationHandler) ApproveComment(ctx echo.Context) error {
+	req := new(web.CommentApprove)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.moderationService.ApproveComment(*req); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "comment not found")
+		}
+
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Summary		Reject a comment
+// @Description	Reject a comment that is waiting for review
+// @Tags			Moderation
+// @Accept			json
+// @Param			id		path	int					true	"Comment ID"
+// @Param			comment	body	web.CommentReject	true	"Rejection Data"
+// @Success		204
+// @Security		BearerAuth
+// @Router			/moderation/comments/{id}/reject [post]
+func (h *moderationHandler) RejectComment(ctx echo.Context) error {
+	req := new(web.CommentReject)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.moderationService.RejectComment(*req); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "comment not found")
+		}
+
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Summary		Get moderation logs
+// @Description	Retrieve the audit trail of moderation decisions, newest first
+// @Tags			Moderation
+// @Produce		json
+// @Param			query	query		web.ModerationLogFindAll	false	"Pagination, sorting and filters"
+// @Success		200		{object}	web.PageResponse[web.ModerationLogResponse]
+// @Security		BearerAuth
+// @Router			/moderation/logs [get]
+func (h *moderationHandler) FindLogs(ctx echo.Context) error {
+	req := new(web.ModerationLogFindAll)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.moderationService.FindLogs(*req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ReactionHandler interface {
+	CreateForum(ctx echo.Context) error
+	DeleteForum(ctx echo.Context) error
+	CreateComment(ctx echo.Context) error
+	DeleteComment(ctx echo.Context) error
+	CreateJournal(ctx echo.Context) error
+	DeleteJournal(ctx echo.Context) error
+}
+
+type reactionHandler struct {
+	reactionService service.ReactionService
+	validator       *validator.Validate
+}
+
+func NewReactionHandler(reactionService service.ReactionService, validator *validator.Validate) ReactionHandler {
+	return &reactionHandler{
+		reactionService: reactionService,
+		validator:       validator,
+	}
+}
+
+// @Summary		React to a forum
+// @Description	Leave a support reaction on a forum post, reacting twice with the same type has no effect
+// @Tags			Reactions
+// @Produce		json
+// @Param			id		path		int		true	"Forum ID"
+// @Param			type	path		string	true	"Reaction type"	Enums(hug, relate, thanks, strength, hope)
+// @Success		200		{object}	web.ReactionSummary
+// @Security		BearerAuth
+// @Router			/forums/{id}/reactions/{type} [put]
+func (h *reactionHandler) CreateForum(ctx echo.Context) error {
+	return h.create(ctx, domain.ForumTarget)
+}
+
+// @Summary		Remove a forum reaction
+// @Description	Remove a reaction left on a forum post
+// @Tags			Reactions
+// @Param			id		path	int		true	"Forum ID"
+// @Param			type	path	string	true	"Reaction type"	Enums(hug, relate, thanks, strength, hope)
+// @Success		204
+// @Security		BearerAuth
+// @Router			/forums/{id}/reactions/{type} [delete]
+func (h *reactionHandler) DeleteForum(ctx echo.Context) error {
+	return h.delete(ctx, domain.ForumTarget)
+}
+
+// @Summary		React to a comment
+// @Description	Leave a support reaction on a comment, reacting twice with the same type has no effect
+// @Tags			Reactions
+// @Produce		json
+// @Param			id		path		int		true	"Comment ID"
+// @Param			type	path		string	true	"Reaction type"	Enums(hug, relate, thanks, strength, hope)
+// @Success		200		{object}	web.ReactionSummary
+// @Security		BearerAuth
+// @Router			/comments/{id}/reactions/{type} [put]
+func (h *reactionHandler) CreateComment(ctx echo.Context) error {
+	return h.create(ctx, domain.CommentTarget)
+}
+
+// @Summary		Remove a comment reaction
+// @Description	Remove a reaction left on a comment
+// @Tags			Reactions
+// @Param			id		path	int		true	"Comment ID"
+// @Param			type	path	string	true	"Reaction type"	Enums(hug, relate, thanks, strength, hope)
+// @Success		204
+// @Security		BearerAuth
+// @Router			/comments/{id}/reactions/{type} [delete]
+func (h *reactionHandler) DeleteComment(ctx echo.Context) error {
+	return h.delete(ctx, domain.CommentTarget)
+}
+
+// @Summary		React to a journal
+// @Description	Leave a support reaction on a public journal, reacting twice with the same type has no effect
+// @Tags			Reactions
+// @Produce		json
+// @Param			id		path		int		true	"Journal ID"
+// @Param			type	path		string	true	"Reaction type"	Enums(hug, relate, thanks, strength, hope)
+// @Success		200		{object}	web.ReactionSummary
+// @Security		BearerAuth
+// @Router			/journals/{id}/reactions/{type} [put]
+func (h *reactionHandler) CreateJournal(ctx echo.Context) error {
+	return h.create(ctx, domain.JournalTarget)
+}
+
+// @Summary		Remove a journal reaction
+// @Description	Remove a reaction left on a journal
+// @Tags			Reactions
+// @Param			id		path	int		true	"Journal ID"
+// @Param			type	path	string	true	"Reaction type"	Enums(hug, relate, thanks, strength, hope)
+// @Success		204
+// @Security		BearerAuth
+// @Router			/journals/{id}/reactions/{type} [delete]
+func (h *reactionHandler) DeleteJournal(ctx echo.Context) error {
+	return h.delete(ctx, domain.JournalTarget)
+}
+
+func (h *reactionHandler) create(ctx echo.Context, target domain.ReactionTarget) error {
+	req := new(web.ReactionCreate)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+	req.TargetType = target
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.reactionService.Create(*req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, string(target)+" not found")
+		}
+
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+func (h *reactionHandler) delete(ctx echo.Context, target domain.ReactionTarget) error {
+	req := new(web.ReactionDelete)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+	req.TargetType = target
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.reactionService.Delete(*req); err != nil {
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type SearchHandler interface {
+	Search(ctx echo.Context) error
+}
+
+type searchHandler struct {
+	searchService service.SearchService
+	validator     *validator.Validate
+}
+
+func NewSearchHandler(searchService service.SearchService, validator *validator.Validate) SearchHandler {
+	return &searchHandler{
+		searchService: searchService,
+		validator:     validator,
+	}
+}
+
+// @Summary		Search
+// @Description	Full-text search across forums, public journals and topics, best matches first. Snippets are HTML escaped and highlight the matches with <mark> tags. Filtering by topic only returns the forums of that topic.
+// @Tags			Search
+// @Produce		json
+// @Param			query	query		web.Search	true	"Search query, language, filters and page"
+// @Success		200		{object}	web.PageResponse[web.SearchResponse]
+// @Router			/search [get]
+func (h *searchHandler) Search(ctx echo.Context) error {
+	req := new(web.Search)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.searchService.Search(*req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
@@ -59,18 +59,24 @@ func (h *topicHandler) Create(ctx echo.Context) error {
 }
 
 // @Summary		Get All Topics
-// @Description	Get all topics
+// @Description	Get all topics, optionally filtered by name
 // @Tags			Topics
 // @Produce		json
-// @Success		200	{array}	web.TopicResponse
+// @Param			query	query		web.TopicFindAll	false	"Pagination, sorting and filters"
+// @Success		200		{object}	web.PageResponse[web.TopicResponse]
 // @Router			/topics [get]
 func (h *topicHandler) FindAll(ctx echo.Context) error {
-	data, err := h.topicService.FindAll()
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "topics not found")
-		}
+	req := new(web.TopicFindAll)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.topicService.FindAll(*req)
+	if err != nil {
 		return err
 	}
 
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/service"
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type TwoFactorHandler interface {
+	Setup(ctx echo.Context) error
+	Enable(ctx echo.Context) error
+	Disable(ctx echo.Context) error
+	RegenerateRecoveryCodes(ctx echo.Context) error
+}
+
+type twoFactorHandler struct {
+	twoFactorService service.TwoFactorService
+	validator        *validator.Validate
+}
+
+func NewTwoFactorHandler(twoFactorService service.TwoFactorService, validator *validator.Validate) TwoFactorHandler {
+	return &twoFactorHandler{
+		twoFactorService: twoFactorService,
+		validator:        validator,
+	}
+}
+
+// @Summary		Set up two-factor authentication
+// @Description	Create a TOTP secret for the authenticated user. Show the uri as a QR code, two-factor authentication is enabled once a code of it is confirmed.
+// @Tags			Auth
+// @Produce		json
+// @Success		200	{object}	web.TwoFactorSetupResponse
+// @Security		BearerAuth
+// @Router			/auth/2fa/setup [post]
+func (h *twoFactorHandler) Setup(ctx echo.Context) error {
+	req := new(web.TwoFactorSetup)
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	data, err := h.twoFactorService.Setup(*req)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "user not found")
+		}
+
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Enable two-factor authentication
+// @Description	Confirm a code of the secret from setup to enable two-factor authentication. The recovery codes are only shown once.
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			enable	body		web.TwoFactorEnable	true	"Code of the authenticator"
+// @Success		200		{object}	web.TwoFactorRecoveryCodesResponse
+// @Security		BearerAuth
+// @Router			/auth/2fa/enable [post]
+func (h *twoFactorHandler) Enable(ctx echo.Context) error {
+	req := new(web.TwoFactorEnable)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.twoFactorService.Enable(*req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
+
+// @Summary		Disable two-factor authentication
+// @Description	Turn two-factor authentication off with the password and a code of the authenticator or a recovery code
+// @Tags			Auth
+// @Accept			json
+// @Param			disable	body	web.TwoFactorDisable	true	"Password and code"
+// @Success		204
+// @Security		BearerAuth
+// @Router			/auth/2fa/disable [post]
+func (h *twoFactorHandler) Disable(ctx echo.Context) error {
+	req := new(web.TwoFactorDisable)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.twoFactorService.Disable(*req); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "user not found")
+		}
+
+		return err
+	}
+
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// @Summary		Regenerate recovery codes
+// @Description	Replace the recovery codes with new ones, the previous codes stop working
+// @Tags			Auth
+// @Accept			json
+// @Produce		json
+// @Param			code	body		web.TwoFactorRecoveryCodes	true	"Code of the authenticator or a recovery code"
+// @Success		200		{object}	web.TwoFactorRecoveryCodesResponse
+// @Security		BearerAuth
+// @Router			/auth/2fa/recovery-codes [post]
+func (h *twoFactorHandler) RegenerateRecoveryCodes(ctx echo.Context) error {
+	req := new(web.TwoFactorRecoveryCodes)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	data, err := h.twoFactorService.RegenerateRecoveryCodes(*req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, data)
+}
@@ -7,20 +7,16 @@ import (
 	"github.com/aternity/zense/internal/entity/web"
 	"github.com/aternity/zense/internal/service"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
 type UserHandler interface {
-	Login(ctx echo.Context) error
-	Register(ctx echo.Context) error
 	FindMe(ctx echo.Context) error
 	FindAll(ctx echo.Context) error
 	FindByID(ctx echo.Context) error
 	Update(ctx echo.Context) error
-	Delete(ctx echo.Context) error
+	UpdateRole(ctx echo.Context) error
 }
 
 type userHandler struct {
@@ -35,69 +31,6 @@ func NewUserHandler(userService service.UserService, validator *validator.Valida
 	}
 }
 
-// @Summary		User login
-// @Description	Authenticate a user and generate a JWT token
-// @Tags			Users
-// @Accept			json
-// @Produce		json
-// @Param			user	body		web.UserLogin	true	"User Login Request"
-// @Success		200		{object}	web.UserAuth
-// @Router			/auth/login [post]
-func (h *userHandler) Login(ctx echo.Context) error {
-	req := new(web.UserLogin)
-	if err := ctx.Bind(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-	}
-
-	if err := h.validator.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-	}
-
-	data, err := h.userService.Login(*req)
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "user not found")
-		}
-
-		return err
-	}
-
-	return ctx.JSON(http.StatusOK, data)
-}
-
-// @Summary		Register a new user
-// @Description	Register a new user with email and password
-// @Tags			Users
-// @Accept			json
-// @Produce		json
-// @Param			user	body		web.UserRegister	true	"User Register Request"
-// @Success		201		{object}	web.UserResponse
-// @Router			/auth/register [post]
-func (h *userHandler) Register(ctx echo.Context) error {
-	req := new(web.UserRegister)
-	if err := ctx.Bind(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-	}
-
-	if err := h.validator.Struct(req); err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-	}
-
-	data, err := h.userService.Register(*req)
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) {
-			if pgErr.Code == "23505" {
-				return echo.NewHTTPError(http.StatusConflict, "email already registered")
-			}
-		}
-
-		return err
-	}
-
-	return ctx.JSON(http.StatusCreated, data)
-}
-
 // @Summary		Get current user
 // @Description	Retrieve the details of the currently authenticated user based on the JWT token provided
 // @Tags			Users
@@ -108,9 +41,11 @@ func (h *userHandler) Register(ctx echo.Context) error {
 func (h *userHandler) FindMe(ctx echo.Context) error {
 	req := new(web.UserFindMe)
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.ID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -129,18 +64,24 @@ func (h *userHandler) FindMe(ctx echo.Context) error {
 }
 
 // @Summary		Get all users
-// @Description	Retrieve a list of all users
+// @Description	Retrieve a list of users, optionally filtered by name
 // @Tags			Users
 // @Produce		json
-// @Success		200	{array}	web.UserResponse
+// @Param			query	query		web.UserFindAll	false	"Pagination, sorting and filters"
+// @Success		200		{object}	web.PageResponse[web.UserResponse]
 // @Router			/users [get]
 func (h *userHandler) FindAll(ctx echo.Context) error {
-	data, err := h.userService.FindAll()
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return echo.NewHTTPError(http.StatusNotFound, "user not found")
-		}
+	req := new(web.UserFindAll)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
 
+	data, err := h.userService.FindAll(*req)
+	if err != nil {
 		return err
 	}
 
@@ -178,7 +119,7 @@ func (h *userHandler) FindByID(ctx echo.Context) error {
 }
 
 // @Summary		Update a user
-// @Description	Update a user's information
+// @Description	Update a user's information. Changing the email or the password asks for the current password, and the two-factor code when it is enabled. A new email has to be verified again and a new password ends the other sessions.
 // @Tags			Users
 // @Accept			json
 // @Produce		json
@@ -193,9 +134,11 @@ func (h *userHandler) Update(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
@@ -213,30 +156,34 @@ func (h *userHandler) Update(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, data)
 }
 
-// @Summary		Delete a user
-// @Description	Delete a user by their ID
+// @Summary		Update a user's role
+// @Description	Promote or demote a user. Only admins can do this.
 // @Tags			Users
 // @Accept			json
 // @Produce		json
-// @Param			id	path	int	true	"User ID"
-// @Success		204
+// @Param			id		path		int					true	"User ID"
+// @Param			role	body		web.UserUpdateRole	true	"User Role Request"
+// @Success		200		{object}	web.UserResponse
 // @Security		BearerAuth
-// @Router			/users/{id} [delete]
-func (h *userHandler) Delete(ctx echo.Context) error {
-	req := new(web.UserDelete)
+// @Router			/users/{id}/role [put]
+func (h *userHandler) UpdateRole(ctx echo.Context) error {
+	req := new(web.UserUpdateRole)
 	if err := ctx.Bind(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	user := ctx.Get("user").(*jwt.Token)
-	claims := user.Claims.(jwt.MapClaims)
-	req.UserID = uint(claims["user_id"].(float64))
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
 
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	if err := h.userService.Delete(*req); err != nil {
+	data, err := h.userService.UpdateRole(*req)
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return echo.NewHTTPError(http.StatusNotFound, "user not found")
 		}
@@ -244,5 +191,5 @@ func (h *userHandler) Delete(ctx echo.Context) error {
 		return err
 	}
 
-	return ctx.NoContent(http.StatusNoContent)
+	return ctx.JSON(http.StatusOK, data)
 }
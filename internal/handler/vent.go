@@ -1,17 +1,22 @@
 package handler
 
 import (
-	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/aternity/zense/internal/entity/web"
 	"github.com/aternity/zense/internal/service"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
 type VentHandler interface {
 	Chat(ctx echo.Context) error
+	Stream(ctx echo.Context) error
 	Clear(ctx echo.Context) error
 }
 
@@ -42,12 +47,17 @@ func (h *ventHandler) Chat(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
 	if err := h.validator.Struct(req); err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
 
-	c := context.Background()
-	data, err := h.ventService.Chat(c, req)
+	data, err := h.ventService.Chat(ctx.Request().Context(), req)
 	if err != nil {
 		return err
 	}
@@ -55,6 +65,58 @@ func (h *ventHandler) Chat(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, data)
 }
 
+// @Summary		Stream a chat reply from AI
+// @Description	Stream the AI reply as Server-Sent Events. Each "message" event carries a web.VentChunk and the final "done" event carries the full web.VentResponse. Text is screened before it is sent, a sentence at a time. When the reply turns high risk the stream stops before the risky text and the "done" event carries the safe message. Clients that do not accept text/event-stream receive a regular JSON response.
+// @Tags			Vents
+// @Accept			json
+// @Produce		text/event-stream
+// @Produce		json
+// @Param			chat	body		web.VentRequest	true	"Vent Request"
+// @Success		200		{object}	web.VentResponse
+// @Security		BearerAuth
+// @Router			/vents/stream [post]
+func (h *ventHandler) Stream(ctx echo.Context) error {
+	if !strings.Contains(ctx.Request().Header.Get(echo.HeaderAccept), "text/event-stream") {
+		return h.Chat(ctx)
+	}
+
+	req := new(web.VentRequest)
+	if err := ctx.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	if err := h.validator.Struct(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	res := ctx.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	data, err := h.ventService.Stream(ctx.Request().Context(), req, func(chunk string) error {
+		return writeEvent(res, "message", web.VentChunk{Delta: chunk})
+	})
+	if err != nil {
+		// The client is gone, there is nobody left to tell.
+		if ctx.Request().Context().Err() != nil {
+			return nil
+		}
+
+		return writeEvent(res, "error", echo.Map{"message": streamError(err)})
+	}
+
+	return writeEvent(res, "done", data)
+}
+
 // @Summary		Clear chat history
 // @Description	Clear the chat history for the current user
 // @Tags			Vents
@@ -62,6 +124,43 @@ func (h *ventHandler) Chat(ctx echo.Context) error {
 // @Security		BearerAuth
 // @Router			/vents [delete]
 func (h *ventHandler) Clear(ctx echo.Context) error {
-	h.ventService.Clear()
+	req := new(web.VentClear)
+
+	principal, err := requirePrincipal(ctx)
+	if err != nil {
+		return err
+	}
+	req.Principal = principal
+
+	h.ventService.Clear(*req)
 	return ctx.NoContent(http.StatusNoContent)
 }
+
+// streamError is the message of an error ending a stream, told like the echo
+// error handler would: HTTP errors keep their message, the others are logged
+// and hidden from the client.
+func streamError(err error) string {
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		if message, ok := he.Message.(string); ok {
+			return message
+		}
+	}
+
+	logrus.WithError(err).Error("failed to stream vent reply")
+	return http.StatusText(http.StatusInternalServerError)
+}
+
+func writeEvent(res *echo.Response, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+
+	res.Flush()
+	return nil
+}
@@ -1,5 +1,90 @@
 package http
 
-func NewMiddleware() {
+import (
+	"net/http"
+	"strings"
 
+	"github.com/aternity/zense/internal/auth"
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/util"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// Authenticate parses the access token of the Authorization header with parse
+// and puts its principal in the context of the request. Requests without a
+// valid token get 401, unless skipper lets them through without a principal.
+func Authenticate(parse func(token string) (*util.Claims, error), skipper middleware.Skipper) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if skipper(c) {
+				return next(c)
+			}
+
+			token := bearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+			if token == "" {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing or malformed jwt")
+			}
+
+			claims, err := parse(token)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid or expired jwt").SetInternal(err)
+			}
+			if claims.UserID == 0 {
+				return echo.NewHTTPError(http.StatusUnauthorized, "invalid token claims")
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(auth.WithPrincipal(req.Context(), auth.NewPrincipal(claims))))
+
+			return next(c)
+		}
+	}
+}
+
+// bearerToken returns the token of an Authorization header, sent alone or
+// after the Bearer scheme.
+func bearerToken(header string) string {
+	if scheme, token, ok := strings.Cut(header, " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	return strings.TrimSpace(header)
+}
+
+// RequireRole only lets through requests whose principal has one of roles. It
+// must run after Authenticate.
+func RequireRole(roles ...domain.UserRole) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, ok := auth.FromContext(c.Request().Context())
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing or malformed jwt")
+			}
+
+			if !principal.HasRole(roles...) {
+				return echo.NewHTTPError(http.StatusForbidden, "you do not have permission to access this resource")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// RequireVerified only lets through requests whose principal verified their
+// email. It must run after Authenticate.
+func RequireVerified() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, ok := auth.FromContext(c.Request().Context())
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "missing or malformed jwt")
+			}
+
+			if !principal.Verified {
+				return echo.NewHTTPError(http.StatusForbidden, "verify your email address first")
+			}
+
+			return next(c)
+		}
+	}
 }
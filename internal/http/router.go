@@ -1,12 +1,14 @@
 package http
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/MarceloPetrucio/go-scalar-api-reference"
+	"github.com/aternity/zense/internal/entity/domain"
 	"github.com/aternity/zense/internal/handler"
 	"github.com/aternity/zense/internal/util"
-	"github.com/labstack/echo-jwt/v4"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -14,26 +16,41 @@ import (
 type Router struct {
 	e        *echo.Echo
 	jwt      *util.JWT
+	denylist Denylist
 	handlers Handlers
 }
 
+// Denylist tells whether an access token was revoked before it expired.
+type Denylist interface {
+	IsRevoked(tokenID string) (bool, error)
+}
+
 type Handlers struct {
-	User    handler.UserHandler
-	Journal handler.JournalHandler
-	Topic   handler.TopicHandler
-	Comment handler.CommentHandler
-	Forum   handler.ForumHandler
-	Vent    handler.VentHandler
+	Auth       handler.AuthHandler
+	TwoFactor  handler.TwoFactorHandler
+	Identity   handler.IdentityHandler
+	User       handler.UserHandler
+	Journal    handler.JournalHandler
+	Topic      handler.TopicHandler
+	Comment    handler.CommentHandler
+	Forum      handler.ForumHandler
+	Vent       handler.VentHandler
+	Moderation handler.ModerationHandler
+	Reaction   handler.ReactionHandler
+	Search     handler.SearchHandler
+	Account    handler.AccountHandler
 }
 
 func NewRouter(
 	e *echo.Echo,
 	jwt *util.JWT,
+	denylist Denylist,
 	handlers Handlers,
 ) *Router {
 	return &Router{
 		e:        e,
 		jwt:      jwt,
+		denylist: denylist,
 		handlers: handlers,
 	}
 }
@@ -47,6 +64,10 @@ func (r *Router) Run() http.Handler {
 	r.e.GET("/", func(c echo.Context) error {
 		return c.JSON(http.StatusOK, "Welcome To Zense")
 	})
+	r.e.GET("/.well-known/jwks.json", func(c echo.Context) error {
+		c.Response().Header().Set("Cache-Control", "public, max-age=300")
+		return c.JSON(http.StatusOK, r.jwt.JWKS())
+	})
 	api := r.e.Group("/api/v1")
 	r.setupRoutes(api)
 
@@ -58,22 +79,46 @@ func (r *Router) setupCORS() {
 }
 
 func (r *Router) setupJWT() {
-	r.e.Use(echojwt.WithConfig(echojwt.Config{
-		SigningKey:  []byte(r.jwt.Secret),
-		TokenLookup: "header:Authorization",
-		Skipper: func(c echo.Context) bool {
-			switch c.Path() {
-			case "/api/v1/auth/login", "/api/v1/auth/register":
-				return true
-			case "/api/v1/users/me":
-				return false
-			default:
-				return c.Request().Method == http.MethodGet
-			}
-		},
+	r.e.Use(Authenticate(r.parseToken, func(c echo.Context) bool {
+		switch path := c.Path(); {
+		case path == "/.well-known/jwks.json", path == "/api/v1/auth/login", path == "/api/v1/auth/register", path == "/api/v1/auth/refresh",
+			path == "/api/v1/auth/verify-email", path == "/api/v1/auth/forgot-password", path == "/api/v1/auth/reset-password",
+			path == "/api/v1/auth/2fa/verify", path == "/api/v1/auth/oidc/:provider", path == "/api/v1/auth/oidc/:provider/callback":
+			return true
+		case path == "/api/v1/users/me", strings.HasPrefix(path, "/api/v1/users/me/"):
+			// The caller's own data always needs a token.
+			return false
+		default:
+			// Reads are public, but a token is still parsed when present
+			// so they can include the caller's own content.
+			return c.Request().Method == http.MethodGet && c.Request().Header.Get(echo.HeaderAuthorization) == ""
+		}
 	}))
 }
 
+// parseToken validates an access token and refuses the revoked ones. Tokens
+// without an ID cannot be revoked, so they are refused too.
+func (r *Router) parseToken(token string) (*util.Claims, error) {
+	claims, err := r.jwt.ValidateToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.ID == "" {
+		return nil, errors.New("token cannot be revoked")
+	}
+
+	revoked, err := r.denylist.IsRevoked(claims.ID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, errors.New("token has been revoked")
+	}
+
+	return claims, nil
+}
+
 func (r *Router) setupRoutes(api *echo.Group) {
 	auth := api.Group("/auth")
 	users := api.Group("/users")
@@ -82,6 +127,11 @@ func (r *Router) setupRoutes(api *echo.Group) {
 	comments := api.Group("/comments")
 	topics := api.Group("/topics")
 	vents := api.Group("/vents")
+	moderation := api.Group("/moderation", RequireRole(domain.ModeratorUser, domain.AdminUser))
+
+	admin := RequireRole(domain.AdminUser)
+	adminUsers := users.Group("", admin)
+	adminTopics := topics.Group("", admin)
 
 	api.GET("/docs", func(c echo.Context) error {
 		htmlContent, err := scalar.ApiReferenceHTML(&scalar.Options{
@@ -97,40 +147,87 @@ func (r *Router) setupRoutes(api *echo.Group) {
 		return c.HTML(http.StatusOK, htmlContent)
 	})
 
-	auth.POST("/login", r.handlers.User.Login)
-	auth.POST("/register", r.handlers.User.Register)
+	api.GET("/search", r.handlers.Search.Search)
+
+	auth.POST("/login", r.handlers.Auth.Login)
+	auth.POST("/register", r.handlers.Auth.Register)
+	auth.POST("/refresh", r.handlers.Auth.Refresh)
+	auth.POST("/logout", r.handlers.Auth.Logout)
+	auth.POST("/logout-all", r.handlers.Auth.LogoutAll)
+	auth.POST("/verify-email", r.handlers.Auth.VerifyEmail)
+	auth.POST("/verify-email/resend", r.handlers.Auth.ResendVerification)
+	auth.POST("/forgot-password", r.handlers.Auth.ForgotPassword)
+	auth.POST("/reset-password", r.handlers.Auth.ResetPassword)
+	auth.POST("/2fa/verify", r.handlers.Auth.VerifyTwoFactor)
+	auth.POST("/2fa/setup", r.handlers.TwoFactor.Setup)
+	auth.POST("/2fa/enable", r.handlers.TwoFactor.Enable)
+	auth.POST("/2fa/disable", r.handlers.TwoFactor.Disable)
+	auth.POST("/2fa/recovery-codes", r.handlers.TwoFactor.RegenerateRecoveryCodes)
+	auth.GET("/oidc", r.handlers.Auth.OIDCProviders)
+	auth.POST("/oidc/:provider", r.handlers.Auth.StartOIDC)
+	auth.POST("/oidc/:provider/callback", r.handlers.Auth.LoginOIDC)
 
 	users.GET("/me", r.handlers.User.FindMe)
+	users.GET("/me/mood-insights", r.handlers.Journal.MoodInsights)
+	users.GET("/me/journals/export", r.handlers.Journal.Export)
+	users.POST("/me/journals/import", r.handlers.Journal.Import, middleware.BodyLimit("10M"))
+	users.POST("/me/exports", r.handlers.Account.CreateExport)
+	users.GET("/me/exports/:id", r.handlers.Account.FindExport)
+	users.GET("/me/exports/:id/download", r.handlers.Account.DownloadExport)
+	users.GET("/me/deletion", r.handlers.Account.FindDeletion)
+	users.DELETE("/me/deletion", r.handlers.Account.CancelDeletion)
+	users.GET("/me/identities", r.handlers.Identity.FindAll)
+	users.POST("/me/identities/:provider", r.handlers.Identity.Connect)
+	users.POST("/me/identities/:provider/callback", r.handlers.Identity.ConnectCallback)
+	users.DELETE("/me/identities/:id", r.handlers.Identity.Delete)
 	users.GET("", r.handlers.User.FindAll)
 	users.GET("/:id", r.handlers.User.FindByID)
+	users.GET("/:id/journals", r.handlers.Journal.FindByUser)
 	users.PUT("/:id", r.handlers.User.Update)
-	users.DELETE("/:id", r.handlers.User.Delete)
+	users.DELETE("/:id", r.handlers.Account.ScheduleDeletion)
+	adminUsers.PUT("/:id/role", r.handlers.User.UpdateRole)
 
 	journals.POST("", r.handlers.Journal.Create)
 	journals.GET("", r.handlers.Journal.FindAll)
 	journals.GET("/:id", r.handlers.Journal.FindByID)
 	journals.PUT("/:id", r.handlers.Journal.Update)
 	journals.DELETE("/:id", r.handlers.Journal.Delete)
+	journals.POST("/:id/restore", r.handlers.Journal.Restore)
+	journals.PUT("/:id/reactions/:type", r.handlers.Reaction.CreateJournal)
+	journals.DELETE("/:id/reactions/:type", r.handlers.Reaction.DeleteJournal)
 
-	comments.POST("", r.handlers.Comment.Create)
+	comments.POST("", r.handlers.Comment.Create, RequireVerified())
 	comments.GET("", r.handlers.Comment.FindAll)
 	comments.GET("/:id", r.handlers.Comment.FindByID)
 	comments.PUT("/:id", r.handlers.Comment.Update)
 	comments.DELETE("/:id", r.handlers.Comment.Delete)
+	comments.POST("/:id/restore", r.handlers.Comment.Restore)
+	comments.PUT("/:id/reactions/:type", r.handlers.Reaction.CreateComment)
+	comments.DELETE("/:id/reactions/:type", r.handlers.Reaction.DeleteComment)
 
-	topics.POST("", r.handlers.Topic.Create)
 	topics.GET("", r.handlers.Topic.FindAll)
 	topics.GET("/:id", r.handlers.Topic.FindByID)
-	topics.PUT("/:id", r.handlers.Topic.Update)
-	topics.DELETE("/:id", r.handlers.Topic.Delete)
+	adminTopics.POST("", r.handlers.Topic.Create)
+	adminTopics.PUT("/:id", r.handlers.Topic.Update)
+	adminTopics.DELETE("/:id", r.handlers.Topic.Delete)
 
-	forums.POST("", r.handlers.Forum.Create)
+	forums.POST("", r.handlers.Forum.Create, RequireVerified())
 	forums.GET("", r.handlers.Forum.FindAll)
 	forums.GET("/:id", r.handlers.Forum.FindByID)
 	forums.PUT("/:id", r.handlers.Forum.Update)
 	forums.DELETE("/:id", r.handlers.Forum.Delete)
+	forums.POST("/:id/restore", r.handlers.Forum.Restore)
 	forums.DELETE("/:id/topic", r.handlers.Forum.RemoveTopic)
+	forums.GET("/:id/comments", r.handlers.Comment.FindByForum)
+	forums.PUT("/:id/reactions/:type", r.handlers.Reaction.CreateForum)
+	forums.DELETE("/:id/reactions/:type", r.handlers.Reaction.DeleteForum)
 
 	vents.POST("", r.handlers.Vent.Chat)
+	vents.POST("/stream", r.handlers.Vent.Stream)
 	vents.DELETE("", r.handlers.Vent.Clear)
+
+	moderation.GET("/comments", r.handlers.Moderation.FindPendingComments)
+	moderation.POST("/comments/:id/approve", r.handlers.Moderation.ApproveComment)
+	moderation.POST("/comments/:id/reject", r.handlers.Moderation.RejectComment)
+	moderation.GET("/logs", r.handlers.Moderation.FindLogs)
 }
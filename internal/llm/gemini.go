@@ -0,0 +1,76 @@
+package llm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+type gemini struct {
+	client *genai.Client
+	model  string
+}
+
+func NewGemini(ctx context.Context, apiKey string, model string) (Provider, error) {
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return nil, err
+	}
+
+	return &gemini{
+		client: client,
+		model:  model,
+	}, nil
+}
+
+func (g *gemini) Generate(ctx context.Context, prompt string) (string, error) {
+	resp, err := g.client.GenerativeModel(g.model).GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return "", err
+	}
+
+	text := geminiText(resp)
+	if text == "" {
+		return "", ErrEmptyResponse
+	}
+
+	return text, nil
+}
+
+func (g *gemini) Stream(ctx context.Context, prompt string, fn func(chunk string) error) error {
+	iter := g.client.GenerativeModel(g.model).GenerateContentStream(ctx, genai.Text(prompt))
+
+	for {
+		resp, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if text := geminiText(resp); text != "" {
+			if err := fn(text); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func geminiText(resp *genai.GenerateContentResponse) string {
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return ""
+	}
+
+	var text strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if t, ok := part.(genai.Text); ok {
+			text.WriteString(string(t))
+		}
+	}
+
+	return text.String()
+}
@@ -0,0 +1,155 @@
+package llm
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// openAI talks to any server implementing the OpenAI chat completions API,
+// such as a self-hosted vLLM, llama.cpp or Ollama instance.
+type openAI struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	model   string
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+	Stream   bool            `json:"stream,omitempty"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+type openAIStreamResponse struct {
+	Choices []struct {
+		Delta openAIMessage `json:"delta"`
+	} `json:"choices"`
+}
+
+func NewOpenAI(baseURL string, apiKey string, model string) Provider {
+	return &openAI{
+		client:  http.DefaultClient,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+	}
+}
+
+func (o *openAI) Generate(ctx context.Context, prompt string) (string, error) {
+	resp, err := o.do(ctx, openAIRequest{
+		Model:    o.model,
+		Messages: []openAIMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body openAIResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", err
+	}
+
+	if body.Error != nil {
+		return "", fmt.Errorf("llm: %s", body.Error.Message)
+	}
+
+	if len(body.Choices) == 0 || body.Choices[0].Message.Content == "" {
+		return "", ErrEmptyResponse
+	}
+
+	return body.Choices[0].Message.Content, nil
+}
+
+func (o *openAI) Stream(ctx context.Context, prompt string, fn func(chunk string) error) error {
+	resp, err := o.do(ctx, openAIRequest{
+		Model:    o.model,
+		Messages: []openAIMessage{{Role: "user", Content: prompt}},
+		Stream:   true,
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			return nil
+		}
+
+		var event openAIStreamResponse
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			return err
+		}
+
+		if len(event.Choices) == 0 || event.Choices[0].Delta.Content == "" {
+			continue
+		}
+
+		if err := fn(event.Choices[0].Delta.Content); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (o *openAI) do(ctx context.Context, payload any) (*http.Response, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.baseURL+"/chat/completions", bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if o.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+o.apiKey)
+	}
+
+	resp, err := o.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		defer resp.Body.Close()
+
+		var body openAIResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err == nil && body.Error != nil {
+			return nil, fmt.Errorf("llm: %s", body.Error.Message)
+		}
+		return nil, fmt.Errorf("llm: unexpected status %s", resp.Status)
+	}
+
+	return resp, nil
+}
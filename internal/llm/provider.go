@@ -0,0 +1,15 @@
+package llm
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrEmptyResponse = errors.New("llm returned an empty response")
+
+type Provider interface {
+	Generate(ctx context.Context, prompt string) (string, error)
+	// Stream calls fn with each piece of the reply as soon as the backend
+	// produces it. Cancelling ctx aborts the upstream request.
+	Stream(ctx context.Context, prompt string, fn func(chunk string) error) error
+}
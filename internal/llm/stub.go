@@ -0,0 +1,52 @@
+package llm
+
+import (
+	"context"
+	"hash/fnv"
+	"strings"
+)
+
+var stubReplies = []string{
+	"Terima kasih sudah mau bercerita. Aku di sini untuk mendengarkan.",
+	"Kedengarannya itu berat sekali. Perasaanmu sangat wajar.",
+	"Aku paham. Pelan-pelan saja, ceritakan apa yang paling mengganggumu.",
+	"Kamu sudah melakukan yang terbaik. Jangan lupa beri dirimu waktu istirahat.",
+}
+
+// stub is an offline provider for development and CI. It never calls out to
+// the network and always answers the same prompt with the same reply.
+type stub struct{}
+
+func NewStub() Provider {
+	return &stub{}
+}
+
+func (s *stub) Generate(ctx context.Context, prompt string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	return stubReply(prompt), nil
+}
+
+func (s *stub) Stream(ctx context.Context, prompt string, fn func(chunk string) error) error {
+	words := strings.SplitAfter(stubReply(prompt), " ")
+	for _, word := range words {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := fn(word); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func stubReply(prompt string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(prompt))
+
+	return stubReplies[hash.Sum32()%uint32(len(stubReplies))]
+}
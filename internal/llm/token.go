@@ -0,0 +1,10 @@
+package llm
+
+import "unicode/utf8"
+
+// EstimateTokens approximates the number of tokens in text. Most tokenizers
+// average around four characters per token for Latin scripts, which is close
+// enough for budgeting without depending on a specific tokenizer.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
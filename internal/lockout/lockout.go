@@ -0,0 +1,160 @@
+package lockout
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// State is the failed login attempts of a key, an account or an IP address.
+type State struct {
+	Failures     int
+	LastFailedAt time.Time
+	LockedUntil  time.Time
+}
+
+// Store keeps the lockout state. Fail and Lock must be safe to call from
+// several replicas at once.
+type Store interface {
+	// Get returns the state of key, the zero State when it has none.
+	Get(key string) (State, error)
+	// Fail counts a failed attempt of key at now and returns the failures
+	// counted so far. Counting starts over when the last failure was before
+	// since.
+	Fail(key string, now time.Time, since time.Time) (int, error)
+	// Lock refuses the attempts of key until until.
+	Lock(key string, until time.Time) error
+	Reset(key string) error
+	// DeleteExpired removes the keys that last failed before before and are
+	// not locked anymore at now.
+	DeleteExpired(now time.Time, before time.Time) error
+}
+
+type Policy struct {
+	// AccountThreshold and IPThreshold are how many failed attempts within
+	// Window lock an account or an IP address. IP addresses are shared behind
+	// NATs, so their threshold is higher.
+	AccountThreshold int
+	IPThreshold      int
+	// BaseDelay is how long the first lockout lasts, each failure after it
+	// doubles the delay up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Window is how long failures are remembered since the last one.
+	Window time.Duration
+}
+
+// LockedError is returned for attempts made while the account or the IP
+// address is locked.
+type LockedError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("too many failed attempts, retry after %s", e.RetryAfter)
+}
+
+// Tracker counts the failed login attempts of every account and IP address
+// and locks them out with an exponential backoff.
+type Tracker struct {
+	store  Store
+	policy Policy
+	now    func() time.Time
+}
+
+func NewTracker(store Store, policy Policy) *Tracker {
+	return &Tracker{
+		store:  store,
+		policy: policy,
+		now:    time.Now,
+	}
+}
+
+// Check returns a *LockedError when the account or the IP address is locked.
+func (t *Tracker) Check(email string, ip string) error {
+	now := t.now()
+
+	var retryAfter time.Duration
+	for _, key := range t.keys(email, ip) {
+		state, err := t.store.Get(key.name)
+		if err != nil {
+			return err
+		}
+
+		if wait := state.LockedUntil.Sub(now); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		return &LockedError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// Fail records a failed attempt of the account from the IP address, locking
+// either once it reached its threshold.
+func (t *Tracker) Fail(email string, ip string) error {
+	now := t.now()
+
+	for _, key := range t.keys(email, ip) {
+		failures, err := t.store.Fail(key.name, now, now.Add(-t.policy.Window))
+		if err != nil {
+			return err
+		}
+
+		if failures < key.threshold {
+			continue
+		}
+
+		if err := t.store.Lock(key.name, now.Add(t.delay(failures-key.threshold))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Succeed forgets the failed attempts of the account. Those of the IP address
+// are kept, or signing in to one account would hide guessing at others.
+func (t *Tracker) Succeed(email string) error {
+	return t.store.Reset(accountKey(email))
+}
+
+// Cleanup removes the keys whose failures are forgotten.
+func (t *Tracker) Cleanup() error {
+	now := t.now()
+	return t.store.DeleteExpired(now, now.Add(-t.policy.Window))
+}
+
+// delay is the lockout after the nth failure past the threshold.
+func (t *Tracker) delay(n int) time.Duration {
+	if n >= 32 {
+		return t.policy.MaxDelay
+	}
+
+	delay := t.policy.BaseDelay << n
+	if delay <= 0 || delay > t.policy.MaxDelay {
+		return t.policy.MaxDelay
+	}
+	return delay
+}
+
+type key struct {
+	name      string
+	threshold int
+}
+
+func (t *Tracker) keys(email string, ip string) []key {
+	keys := []key{{accountKey(email), t.policy.AccountThreshold}}
+	if ip != "" {
+		keys = append(keys, key{"ip:" + ip, t.policy.IPThreshold})
+	}
+	return keys
+}
+
+// accountKey is the key of an email, whether it is registered or not, so
+// locking out does not tell which accounts exist.
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
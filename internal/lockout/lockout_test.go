@@ -0,0 +1,142 @@
+package lockout
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var testPolicy = Policy{
+	AccountThreshold: 3,
+	IPThreshold:      5,
+	BaseDelay:        time.Minute,
+	MaxDelay:         4 * time.Minute,
+	Window:           time.Hour,
+}
+
+// newTestTracker returns a tracker on a memory store whose clock only moves
+// when the test advances it.
+func newTestTracker() (*Tracker, func(time.Duration)) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker(NewMemory(), testPolicy)
+	tracker.now = func() time.Time { return now }
+	return tracker, func(d time.Duration) { now = now.Add(d) }
+}
+
+func fail(t *testing.T, tracker *Tracker, email string, ip string, times int) {
+	t.Helper()
+	for i := 0; i < times; i++ {
+		if err := tracker.Fail(email, ip); err != nil {
+			t.Fatalf("Fail(%q, %q) = %v", email, ip, err)
+		}
+	}
+}
+
+// retryAfter returns how long Check refuses the attempt for, 0 when it lets
+// it through.
+func retryAfter(t *testing.T, tracker *Tracker, email string, ip string) time.Duration {
+	t.Helper()
+	err := tracker.Check(email, ip)
+	if err == nil {
+		return 0
+	}
+
+	var locked *LockedError
+	if !errors.As(err, &locked) {
+		t.Fatalf("Check(%q, %q) = %v, want a *LockedError", email, ip, err)
+	}
+	return locked.RetryAfter
+}
+
+func TestTrackerLocksAccountAtThreshold(t *testing.T) {
+	tracker, _ := newTestTracker()
+
+	fail(t, tracker, "user@example.com", "", testPolicy.AccountThreshold-1)
+	if got := retryAfter(t, tracker, "user@example.com", ""); got != 0 {
+		t.Fatalf("locked for %s below the threshold", got)
+	}
+
+	fail(t, tracker, "user@example.com", "", 1)
+	if got := retryAfter(t, tracker, " User@Example.com", ""); got != testPolicy.BaseDelay {
+		t.Fatalf("locked for %s at the threshold, want %s", got, testPolicy.BaseDelay)
+	}
+	if got := retryAfter(t, tracker, "other@example.com", ""); got != 0 {
+		t.Fatalf("another account is locked for %s", got)
+	}
+}
+
+func TestTrackerLocksIPAtThreshold(t *testing.T) {
+	tracker, _ := newTestTracker()
+
+	for i := 0; i < testPolicy.IPThreshold-1; i++ {
+		fail(t, tracker, string(rune('a'+i))+"@example.com", "192.0.2.1", 1)
+	}
+	if got := retryAfter(t, tracker, "new@example.com", "192.0.2.1"); got != 0 {
+		t.Fatalf("locked for %s below the threshold", got)
+	}
+
+	fail(t, tracker, "last@example.com", "192.0.2.1", 1)
+	if got := retryAfter(t, tracker, "new@example.com", "192.0.2.1"); got != testPolicy.BaseDelay {
+		t.Fatalf("locked for %s at the threshold, want %s", got, testPolicy.BaseDelay)
+	}
+	if got := retryAfter(t, tracker, "new@example.com", "192.0.2.2"); got != 0 {
+		t.Fatalf("another address is locked for %s", got)
+	}
+}
+
+func TestTrackerDoublesDelayUpToMax(t *testing.T) {
+	tracker, advance := newTestTracker()
+
+	fail(t, tracker, "user@example.com", "", testPolicy.AccountThreshold-1)
+	for _, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		fail(t, tracker, "user@example.com", "", 1)
+
+		got := retryAfter(t, tracker, "user@example.com", "")
+		if got != want {
+			t.Fatalf("locked for %s, want %s", got, want)
+		}
+
+		advance(got)
+		if got := retryAfter(t, tracker, "user@example.com", ""); got != 0 {
+			t.Fatalf("still locked for %s once the delay is over", got)
+		}
+	}
+}
+
+func TestTrackerForgetsFailuresAfterWindow(t *testing.T) {
+	tracker, advance := newTestTracker()
+
+	fail(t, tracker, "user@example.com", "", testPolicy.AccountThreshold-1)
+	advance(testPolicy.Window + time.Second)
+
+	fail(t, tracker, "user@example.com", "", 1)
+	if got := retryAfter(t, tracker, "user@example.com", ""); got != 0 {
+		t.Fatalf("locked for %s by failures older than the window", got)
+	}
+
+	fail(t, tracker, "user@example.com", "", testPolicy.AccountThreshold-1)
+	if got := retryAfter(t, tracker, "user@example.com", ""); got != testPolicy.BaseDelay {
+		t.Fatalf("locked for %s once the threshold is reached again, want %s", got, testPolicy.BaseDelay)
+	}
+}
+
+func TestTrackerSucceedKeepsIP(t *testing.T) {
+	tracker, _ := newTestTracker()
+
+	fail(t, tracker, "user@example.com", "192.0.2.1", testPolicy.IPThreshold)
+	if err := tracker.Succeed("user@example.com"); err != nil {
+		t.Fatalf("Succeed() = %v", err)
+	}
+
+	if got := retryAfter(t, tracker, "user@example.com", "192.0.2.2"); got != 0 {
+		t.Fatalf("account still locked for %s after Succeed", got)
+	}
+	if got := retryAfter(t, tracker, "other@example.com", "192.0.2.1"); got != testPolicy.BaseDelay {
+		t.Fatalf("address locked for %s after Succeed, want %s", got, testPolicy.BaseDelay)
+	}
+
+	fail(t, tracker, "user@example.com", "192.0.2.2", testPolicy.AccountThreshold-1)
+	if got := retryAfter(t, tracker, "user@example.com", "192.0.2.2"); got != 0 {
+		t.Fatalf("account locked for %s by failures from before Succeed", got)
+	}
+}
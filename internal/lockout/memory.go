@@ -0,0 +1,73 @@
+package lockout
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryStore keeps the lockout state in the process. Every replica counts
+// on its own, so it is meant for single instances and local development.
+type memoryStore struct {
+	mu     sync.Mutex
+	states map[string]State
+}
+
+func NewMemory() Store {
+	return &memoryStore{
+		states: make(map[string]State),
+	}
+}
+
+func (s *memoryStore) Get(key string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.states[key], nil
+}
+
+func (s *memoryStore) Fail(key string, now time.Time, since time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.states[key]
+	if state.LastFailedAt.Before(since) {
+		state.Failures = 0
+	}
+	state.Failures++
+	state.LastFailedAt = now
+	s.states[key] = state
+
+	return state.Failures, nil
+}
+
+func (s *memoryStore) Lock(key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.states[key]
+	state.LockedUntil = until
+	s.states[key] = state
+
+	return nil
+}
+
+func (s *memoryStore) Reset(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.states, key)
+	return nil
+}
+
+func (s *memoryStore) DeleteExpired(now time.Time, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, state := range s.states {
+		if state.LastFailedAt.Before(before) && !state.LockedUntil.After(now) {
+			delete(s.states, key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package lockout
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// postgresStore keeps the lockout state in the database, shared by every
+// replica. Failures are counted with a single upsert, so concurrent attempts
+// are all counted.
+type postgresStore struct {
+	db *gorm.DB
+}
+
+type loginAttempt struct {
+	Key          string `gorm:"primaryKey"`
+	Failures     int
+	LastFailedAt time.Time `gorm:"index"`
+	LockedUntil  *time.Time
+}
+
+func (loginAttempt) TableName() string {
+	return "login_attempts"
+}
+
+// NewPostgres creates the login_attempts table when it does not exist yet.
+func NewPostgres(db *gorm.DB) (Store, error) {
+	if err := db.AutoMigrate(&loginAttempt{}); err != nil {
+		return nil, err
+	}
+
+	return &postgresStore{
+		db: db,
+	}, nil
+}
+
+func (s *postgresStore) Get(key string) (State, error) {
+	var attempt loginAttempt
+	if err := s.db.Where("key = ?", key).First(&attempt).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return State{}, nil
+		}
+		return State{}, err
+	}
+
+	state := State{
+		Failures:     attempt.Failures,
+		LastFailedAt: attempt.LastFailedAt,
+	}
+	if attempt.LockedUntil != nil {
+		state.LockedUntil = *attempt.LockedUntil
+	}
+
+	return state, nil
+}
+
+func (s *postgresStore) Fail(key string, now time.Time, since time.Time) (int, error) {
+	var failures int
+	err := s.db.Raw(`INSERT INTO login_attempts (key, failures, last_failed_at) VALUES (?, 1, ?)
+ON CONFLICT (key) DO UPDATE SET
+	failures = CASE WHEN login_attempts.last_failed_at < ? THEN 1 ELSE login_attempts.failures + 1 END,
+	last_failed_at = EXCLUDED.last_failed_at
+RETURNING failures`, key, now, since).Scan(&failures).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return failures, nil
+}
+
+func (s *postgresStore) Lock(key string, until time.Time) error {
+	return s.db.Model(&loginAttempt{}).Where("key = ?", key).Update("locked_until", until).Error
+}
+
+func (s *postgresStore) Reset(key string) error {
+	return s.db.Where("key = ?", key).Delete(&loginAttempt{}).Error
+}
+
+func (s *postgresStore) DeleteExpired(now time.Time, before time.Time) error {
+	return s.db.Where("last_failed_at < ? AND (locked_until IS NULL OR locked_until <= ?)", before, now).
+		Delete(&loginAttempt{}).Error
+}
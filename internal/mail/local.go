@@ -0,0 +1,55 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fileMailer writes every message to an .eml file in dir instead of sending
+// it, for local development.
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFile(dir string, from string) Mailer {
+	return &fileMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (m *fileMailer) Send(ctx context.Context, message Message) error {
+	data, err := format(m.from, message)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(m.dir, 0o700); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102T150405.000000000"), filepath.Base(message.To))
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o600)
+}
+
+// logMailer logs every message instead of sending it. Bodies carry links with
+// tokens, so it is only meant for local development.
+type logMailer struct{}
+
+func NewLog() Mailer {
+	return &logMailer{}
+}
+
+func (m *logMailer) Send(ctx context.Context, message Message) error {
+	logrus.WithFields(logrus.Fields{
+		"to":      message.To,
+		"subject": message.Subject,
+	}).Info(message.Body)
+	return nil
+}
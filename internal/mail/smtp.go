@@ -0,0 +1,97 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+)
+
+// smtpMailer sends mail through an SMTP server, upgrading the connection with
+// STARTTLS when the server offers it. Servers without STARTTLS are refused
+// when requireTLS is set, so neither credentials nor mail go in plain text.
+// Authentication is skipped when no username is set, as local sinks such as
+// Mailpit expect.
+type smtpMailer struct {
+	host       string
+	port       string
+	username   string
+	password   string
+	from       string
+	requireTLS bool
+}
+
+func NewSMTP(host string, port string, username string, password string, from string, requireTLS bool) Mailer {
+	return &smtpMailer{
+		host:       host,
+		port:       port,
+		username:   username,
+		password:   password,
+		from:       from,
+		requireTLS: requireTLS,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message Message) error {
+	data, err := format(m.from, message)
+	if err != nil {
+		return err
+	}
+
+	// The envelope takes the bare address, the From header keeps the name.
+	from, err := netmail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(m.host, m.port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	} else if m.requireTLS {
+		return errors.New("smtp server does not offer STARTTLS")
+	}
+
+	if m.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,71 @@
+package migration
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migration changes the schema in ways AutoMigrate cannot, such as generated
+// columns and their indexes.
+type Migration struct {
+	Version string
+	Up      func(tx *gorm.DB) error
+}
+
+// Migrations lists every migration in the order it is applied. Applied
+// migrations must never be edited, changes go in a new one.
+var Migrations = []Migration{
+	{Version: "0001_search", Up: search},
+	{Version: "0002_verified_users", Up: verifiedUsers},
+	{Version: "0003_deleted_user", Up: deletedUser},
+}
+
+type schemaMigration struct {
+	Version   string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Run applies the migrations that are not recorded in schema_migrations yet,
+// each one in its own transaction.
+func Run(db *gorm.DB) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return err
+	}
+
+	var versions []string
+	if err := db.Model(&schemaMigration{}).Pluck("version", &versions).Error; err != nil {
+		return err
+	}
+
+	applied := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		applied[version] = true
+	}
+
+	for _, migration := range Migrations {
+		if applied[migration.Version] {
+			continue
+		}
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				AppliedAt: time.Now(),
+			}).Error
+		}); err != nil {
+			return fmt.Errorf("migration %s: %w", migration.Version, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package migration
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// search adds a generated tsvector column per search language to forums,
+// journals and topics, with their GIN indexes. Builds of PostgreSQL without
+// the indonesian configuration get zense_indonesian copied from simple, which
+// matches words without stemming them.
+func search(tx *gorm.DB) error {
+	var indonesian bool
+	if err := tx.Raw("SELECT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'indonesian')").Scan(&indonesian).Error; err != nil {
+		return err
+	}
+
+	parser := "simple"
+	if indonesian {
+		parser = "indonesian"
+	}
+
+	statements := []string{
+		fmt.Sprintf("CREATE TEXT SEARCH CONFIGURATION zense_indonesian (COPY = %s)", parser),
+	}
+
+	documents := []struct {
+		table    string
+		document string
+	}{
+		{"forums", "setweight(to_tsvector('%[1]s', coalesce(title, '')), 'A') || setweight(to_tsvector('%[1]s', coalesce(content, '')), 'B')"},
+		{"journals", "to_tsvector('%[1]s', coalesce(content, ''))"},
+		{"topics", "setweight(to_tsvector('%[1]s', coalesce(name, '')), 'A') || setweight(to_tsvector('%[1]s', coalesce(description, '')), 'B')"},
+	}
+
+	languages := []struct {
+		column string
+		config string
+	}{
+		{"tsv_english", "english"},
+		{"tsv_indonesian", "zense_indonesian"},
+	}
+
+	for _, d := range documents {
+		for _, l := range languages {
+			statements = append(statements,
+				fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s tsvector GENERATED ALWAYS AS (%s) STORED", d.table, l.column, fmt.Sprintf(d.document, l.config)),
+				fmt.Sprintf("CREATE INDEX idx_%s_%s ON %s USING GIN (%s)", d.table, l.column, d.table, l.column),
+			)
+		}
+	}
+
+	for _, statement := range statements {
+		if err := tx.Exec(statement).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
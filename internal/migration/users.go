@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"errors"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+)
+
+// verifiedUsers marks the users who signed up before email verification
+// existed as verified, so they can keep posting.
+func verifiedUsers(tx *gorm.DB) error {
+	return tx.Exec("UPDATE users SET verified_at = created_at WHERE verified_at IS NULL").Error
+}
+
+// deletedUser seeds the account owning the content of erased accounts. Its
+// address could be signed up with before it was reserved, then the migration
+// stops until that account's email is changed.
+func deletedUser(tx *gorm.DB) error {
+	if err := tx.Exec(`INSERT INTO users (name, email, password, role, created_at, updated_at)
+		SELECT 'Deleted user', ?, '', ?, now(), now()
+		WHERE NOT EXISTS (SELECT 1 FROM users WHERE email = ?)`,
+		domain.DeletedUserEmail, domain.DeletedUser, domain.DeletedUserEmail).Error; err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Table("users").Where("role = ?", domain.DeletedUser).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return errors.New("an account signed up with " + domain.DeletedUserEmail + ", change its email so the deleted user can have it")
+	}
+
+	return nil
+}
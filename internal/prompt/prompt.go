@@ -0,0 +1,158 @@
+package prompt
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+//go:embed templates
+var defaults embed.FS
+
+var ErrNotFound = errors.New("prompt template not found")
+
+// Data is what every prompt template is rendered with.
+type Data struct {
+	Summary string
+	History string
+	Message string
+}
+
+type Template struct {
+	Persona  string
+	Language string
+	Version  string
+	Weight   int
+	tmpl     *template.Template
+}
+
+func (t *Template) Render(data Data) (string, error) {
+	var prompt strings.Builder
+	if err := t.tmpl.Execute(&prompt, data); err != nil {
+		return "", err
+	}
+	return prompt.String(), nil
+}
+
+// Registry holds every known template version of each persona and language.
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string][]*Template
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		templates: map[string][]*Template{},
+	}
+}
+
+// Add parses body and registers it, replacing an existing template with the
+// same persona, language and version.
+func (r *Registry) Add(persona, language, version, body string, weight int) error {
+	tmpl, err := template.New(persona + "/" + language + "/" + version).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return err
+	}
+
+	if weight <= 0 {
+		weight = 1
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := key(persona, language)
+	versions := r.templates[k]
+	for i, t := range versions {
+		if t.Version == version {
+			versions = append(versions[:i], versions[i+1:]...)
+			break
+		}
+	}
+
+	versions = append(versions, &Template{
+		Persona:  persona,
+		Language: language,
+		Version:  version,
+		Weight:   weight,
+		tmpl:     tmpl,
+	})
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version < versions[j].Version
+	})
+	r.templates[k] = versions
+
+	return nil
+}
+
+// Pick chooses one of the versions registered for a persona and language.
+// The choice is weighted and stable per user, so the same user keeps getting
+// the same variant while an experiment runs.
+func (r *Registry) Pick(persona, language string, userID uint) (*Template, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	versions := r.templates[key(persona, language)]
+	if len(versions) == 0 {
+		return nil, ErrNotFound
+	}
+
+	total := 0
+	for _, t := range versions {
+		total += t.Weight
+	}
+
+	hash := fnv.New32a()
+	fmt.Fprintf(hash, "%d:%s:%s", userID, persona, language)
+	bucket := int(hash.Sum32() % uint32(total))
+
+	for _, t := range versions {
+		if bucket < t.Weight {
+			return t, nil
+		}
+		bucket -= t.Weight
+	}
+
+	return versions[len(versions)-1], nil
+}
+
+// LoadFS registers every template laid out as <persona>/<language>/<version>.tmpl.
+func (r *Registry) LoadFS(fsys fs.FS) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != ".tmpl" {
+			return err
+		}
+
+		parts := strings.Split(p, "/")
+		if len(parts) != 3 {
+			return fmt.Errorf("prompt template %s: expected <persona>/<language>/<version>.tmpl", p)
+		}
+
+		body, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+
+		return r.Add(parts[0], parts[1], strings.TrimSuffix(parts[2], ".tmpl"), string(body), 1)
+	})
+}
+
+// LoadDefaults registers the templates shipped with the binary.
+func (r *Registry) LoadDefaults() error {
+	fsys, err := fs.Sub(defaults, "templates")
+	if err != nil {
+		return err
+	}
+	return r.LoadFS(fsys)
+}
+
+func key(persona, language string) string {
+	return persona + "/" + language
+}
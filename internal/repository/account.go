@@ -0,0 +1,278 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AccountRepository interface {
+	CreateExport(export *domain.DataExport) (*domain.DataExport, error)
+	FindExport(id uint) (*domain.DataExport, error)
+	FindActiveExport(userID uint) (*domain.DataExport, error)
+	FindExportsByUser(userID uint) ([]domain.DataExport, error)
+	FindExpiredExports(now time.Time) ([]domain.DataExport, error)
+	ClaimExport() (*domain.DataExport, error)
+	ResetRunningExports() error
+	UpdateExport(export *domain.DataExport) (*domain.DataExport, error)
+	DeleteExport(export *domain.DataExport) error
+	FindData(userID uint) (*AccountData, error)
+	SaveDeletion(deletion *domain.AccountDeletion) (*domain.AccountDeletion, error)
+	FindDeletion(userID uint) (*domain.AccountDeletion, error)
+	FindDueDeletions(now time.Time) ([]domain.AccountDeletion, error)
+	DeleteDeletion(deletion *domain.AccountDeletion) error
+	Erase(userID uint, mode domain.ErasureMode) error
+}
+
+// AccountData is everything stored about a user, as bundled in their data
+// export. Deleted journals, forums and comments are included until they are
+// purged.
+type AccountData struct {
+	User      domain.User
+	Journals  []domain.Journal
+	Forums    []domain.Forum
+	Comments  []domain.Comment
+	Reactions []domain.Reaction
+}
+
+type accountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) AccountRepository {
+	return &accountRepository{
+		db: db,
+	}
+}
+
+func (r *accountRepository) CreateExport(export *domain.DataExport) (*domain.DataExport, error) {
+	if err := r.db.Create(&export).Error; err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (r *accountRepository) FindExport(id uint) (*domain.DataExport, error) {
+	var export domain.DataExport
+	if err := r.db.First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindActiveExport returns the export of userID that is still waiting for
+// or being built by the worker.
+func (r *accountRepository) FindActiveExport(userID uint) (*domain.DataExport, error) {
+	var export domain.DataExport
+	if err := r.db.Where("user_id = ? AND status IN ?", userID, []domain.ExportStatus{domain.PendingExport, domain.RunningExport}).
+		First(&export).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+func (r *accountRepository) FindExportsByUser(userID uint) ([]domain.DataExport, error) {
+	exports := []domain.DataExport{}
+	if err := r.db.Where("user_id = ?", userID).Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+func (r *accountRepository) FindExpiredExports(now time.Time) ([]domain.DataExport, error) {
+	exports := []domain.DataExport{}
+	if err := r.db.Where("expires_at < ?", now).Find(&exports).Error; err != nil {
+		return nil, err
+	}
+	return exports, nil
+}
+
+// ClaimExport marks the oldest pending export as running and returns it, or
+// gorm.ErrRecordNotFound when there is none. Locked rows are skipped, so
+// several workers never claim the same export.
+func (r *accountRepository) ClaimExport() (*domain.DataExport, error) {
+	var export domain.DataExport
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", domain.PendingExport).
+			Order("id").
+			First(&export).Error; err != nil {
+			return err
+		}
+
+		export.Status = domain.RunningExport
+		return tx.Model(&export).Update("status", export.Status).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ResetRunningExports puts the exports interrupted by a restart back in the
+// queue.
+func (r *accountRepository) ResetRunningExports() error {
+	return r.db.Model(&domain.DataExport{}).Where("status = ?", domain.RunningExport).Update("status", domain.PendingExport).Error
+}
+
+func (r *accountRepository) UpdateExport(export *domain.DataExport) (*domain.DataExport, error) {
+	if err := r.db.Updates(&export).Error; err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (r *accountRepository) DeleteExport(export *domain.DataExport) error {
+	return r.db.Delete(&export).Error
+}
+
+func (r *accountRepository) FindData(userID uint) (*AccountData, error) {
+	var data AccountData
+	if err := r.db.First(&data.User, userID).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Unscoped().Where("user_id = ?", userID).Order("created_at, id").Find(&data.Journals).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Unscoped().Where("user_id = ?", userID).Order("created_at, id").Preload("Topics").Find(&data.Forums).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Unscoped().Where("user_id = ?", userID).Order("created_at, id").Find(&data.Comments).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("user_id = ?", userID).Order("created_at, id").Find(&data.Reactions).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// SaveDeletion schedules the deletion of an account, replacing the schedule
+// the account already had.
+func (r *accountRepository) SaveDeletion(deletion *domain.AccountDeletion) (*domain.AccountDeletion, error) {
+	if err := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mode", "scheduled_for", "created_at"}),
+	}).Create(&deletion).Error; err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+func (r *accountRepository) FindDeletion(userID uint) (*domain.AccountDeletion, error) {
+	var deletion domain.AccountDeletion
+	if err := r.db.Where("user_id = ?", userID).First(&deletion).Error; err != nil {
+		return nil, err
+	}
+	return &deletion, nil
+}
+
+func (r *accountRepository) FindDueDeletions(now time.Time) ([]domain.AccountDeletion, error) {
+	deletions := []domain.AccountDeletion{}
+	if err := r.db.Where("scheduled_for <= ?", now).Order("scheduled_for").Find(&deletions).Error; err != nil {
+		return nil, err
+	}
+	return deletions, nil
+}
+
+func (r *accountRepository) DeleteDeletion(deletion *domain.AccountDeletion) error {
+	return r.db.Delete(&deletion).Error
+}
+
+// Erase deletes an account in a single transaction, following the erasure
+// policy: journals, reactions, vent and safety records, data exports,
+// sessions and the forums and comments the account deleted are always
+// deleted, and the access tokens of the account are revoked. The other
+// forums and comments of the account go to the deleted user, with their text
+// erased first in the remove mode. Moderation logs keep their entries under
+// the deleted user.
+func (r *accountRepository) Erase(userID uint, mode domain.ErasureMode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		deleted := domain.User{}
+		if err := tx.Where("role = ?", domain.DeletedUser).First(&deleted).Error; err != nil {
+			return err
+		}
+
+		journals := tx.Unscoped().Model(&domain.Journal{}).Select("id").Where("user_id = ?", userID)
+		if err := tx.Where("user_id = ? OR (target_type = ? AND target_id IN (?))", userID, domain.JournalTarget, journals).
+			Delete(&domain.Reaction{}).Error; err != nil {
+			return err
+		}
+
+		if err := revokeTokens(tx, "user_id = ?", userID); err != nil {
+			return err
+		}
+
+		for _, model := range []any{&domain.Journal{}, &domain.SafetyEvent{}, &domain.VentReply{}, &domain.DataExport{}, &domain.AccountDeletion{}, &domain.RefreshToken{}, &domain.UserToken{}, &domain.RecoveryCode{}, &domain.TwoFactor{}, &domain.Identity{}, &domain.OIDCState{}} {
+			if err := tx.Unscoped().Where("user_id = ?", userID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		// Deleted forums and comments are not kept until the end of the
+		// retention period, and placeholders lose the content they kept.
+		// Deleted comments somebody replied to since become placeholders
+		// too, so the replies survive.
+		if _, err := purgeForums(tx, tx.Unscoped().Model(&domain.Forum{}).Select("id").Where("user_id = ? AND deleted_at IS NOT NULL", userID)); err != nil {
+			return err
+		}
+		replied := tx.Model(&domain.Comment{}).Select("parent_id").Where("parent_id IS NOT NULL")
+		if err := tx.Unscoped().Model(&domain.Comment{}).
+			Where("user_id = ? AND deleted_at IS NOT NULL AND id IN (?)", userID, replied).
+			Updates(map[string]any{"content": domain.DeletedCommentContent, "deleted": true, "deleted_at": nil}).Error; err != nil {
+			return err
+		}
+		if _, err := purgeComments(tx, tx.Unscoped().Model(&domain.Comment{}).Select("id").Where("user_id = ? AND deleted_at IS NOT NULL", userID)); err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Comment{}).Where("user_id = ? AND deleted = ?", userID, true).
+			UpdateColumn("content", domain.DeletedCommentContent).Error; err != nil {
+			return err
+		}
+
+		if mode == domain.RemoveErasure {
+			if err := r.remove(tx, userID); err != nil {
+				return err
+			}
+		}
+
+		for _, model := range []any{&domain.Forum{}, &domain.Comment{}} {
+			if err := tx.Model(model).Where("user_id = ?", userID).UpdateColumn("user_id", deleted.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&domain.ModerationLog{}).Where("moderator_id = ?", userID).Update("moderator_id", deleted.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&domain.User{}, userID).Error
+	})
+}
+
+// remove erases the forums and comments of userID. Posts nobody replied to
+// are deleted, the others keep a placeholder so the replies survive.
+func (r *accountRepository) remove(tx *gorm.DB, userID uint) error {
+	replied := tx.Model(&domain.Comment{}).Select("parent_id").Where("parent_id IS NOT NULL")
+
+	if err := tx.Model(&domain.Comment{}).
+		Where("user_id = ? AND id IN (?)", userID, replied).
+		Updates(map[string]any{"content": domain.DeletedCommentContent, "deleted": true}).Error; err != nil {
+		return err
+	}
+
+	if _, err := purgeComments(tx, tx.Model(&domain.Comment{}).Select("id").Where("user_id = ? AND id NOT IN (?)", userID, replied)); err != nil {
+		return err
+	}
+
+	if err := tx.Model(&domain.Forum{}).
+		Where("user_id = ? AND EXISTS (SELECT 1 FROM comments WHERE comments.forum_id = forums.id)", userID).
+		Updates(map[string]any{"title": domain.DeletedForumContent, "content": domain.DeletedForumContent}).Error; err != nil {
+		return err
+	}
+
+	_, err := purgeForums(tx, tx.Model(&domain.Forum{}).Select("id").
+		Where("user_id = ? AND NOT EXISTS (SELECT 1 FROM comments WHERE comments.forum_id = forums.id)", userID))
+	return err
+}
@@ -1,16 +1,33 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/aternity/zense/internal/entity/domain"
 	"gorm.io/gorm"
 )
 
 type CommentRepository interface {
 	Create(comment *domain.Comment) (*domain.Comment, error)
-	FindAll() ([]domain.Comment, error)
+	FindAll(userID uint, filter CommentFilter, pagination Pagination) (*Page[domain.Comment], error)
+	FindByVisibility(visibility domain.CommentVisibility, pagination Pagination) (*Page[domain.Comment], error)
+	FindByForum(forumID uint, parentID *uint, userID uint, pagination Pagination) (*Page[domain.Comment], error)
+	FindReplies(parentIDs []uint, userID uint, limit int) (map[uint]*Page[domain.Comment], error)
+	CountReplies(id uint) (int64, error)
 	FindByID(id uint) (*domain.Comment, error)
 	Update(comment *domain.Comment) (*domain.Comment, error)
 	Delete(comment *domain.Comment) error
+	FindDeleted(id uint) (*domain.Comment, error)
+	Restore(comment *domain.Comment, log *domain.ModerationLog) error
+	Purge(before time.Time) (int64, error)
+}
+
+type CommentFilter struct {
+	ForumID uint
+	UserID  uint
+	// ExcludeAnonymous leaves out anonymous comments, so filtering by author
+	// does not reveal who wrote them.
+	ExcludeAnonymous bool
 }
 
 type commentRepository struct {
@@ -30,17 +47,101 @@ func (r *commentRepository) Create(comment *domain.Comment) (*domain.Comment, er
 	return comment, nil
 }
 
-func (r *commentRepository) FindAll() ([]domain.Comment, error) {
-	var comments []domain.Comment
-	if err := r.db.Preload("User").Find(&comments).Error; err != nil {
+// FindAll returns the public comments and every comment written by userID.
+func (r *commentRepository) FindAll(userID uint, filter CommentFilter, pagination Pagination) (*Page[domain.Comment], error) {
+	query := r.db.Model(&domain.Comment{}).Where("visibility = ? OR user_id = ?", domain.PublicComment, userID)
+	if filter.ForumID != 0 {
+		query = query.Where("forum_id = ?", filter.ForumID)
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ExcludeAnonymous {
+		query = query.Where("anonymous = ?", false)
+	}
+
+	return paginate(query, pagination, commentSortable, commentKey, "User")
+}
+
+func (r *commentRepository) FindByVisibility(visibility domain.CommentVisibility, pagination Pagination) (*Page[domain.Comment], error) {
+	query := r.db.Model(&domain.Comment{}).Where("visibility = ?", visibility)
+	return paginate(query, pagination, commentSortable, commentKey, "User")
+}
+
+// FindByForum returns one level of the thread of a forum, the top level when
+// parentID is nil or the direct replies of parentID otherwise.
+func (r *commentRepository) FindByForum(forumID uint, parentID *uint, userID uint, pagination Pagination) (*Page[domain.Comment], error) {
+	query := r.db.Model(&domain.Comment{}).Where("forum_id = ? AND (visibility = ? OR user_id = ?)", forumID, domain.PublicComment, userID)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+
+	return paginate(query, pagination, commentSortable, commentKey, "User")
+}
+
+// FindReplies loads the first limit replies of every parent in parentIDs,
+// oldest first, in a single query. The pages carry the cursor of the next
+// replies when a parent has more of them.
+func (r *commentRepository) FindReplies(parentIDs []uint, userID uint, limit int) (map[uint]*Page[domain.Comment], error) {
+	pages := make(map[uint]*Page[domain.Comment], len(parentIDs))
+	if len(parentIDs) == 0 {
+		return pages, nil
+	}
+
+	visible := r.db.Model(&domain.Comment{}).
+		Where("parent_id IN ? AND (visibility = ? OR user_id = ?)", parentIDs, domain.PublicComment, userID).
+		Session(&gorm.Session{})
+
+	var counts []struct {
+		ParentID uint
+		Total    int64
+	}
+	if err := visible.Select("parent_id, COUNT(*) AS total").Group("parent_id").Scan(&counts).Error; err != nil {
 		return nil, err
 	}
 
-	if len(comments) == 0 {
-		return nil, gorm.ErrRecordNotFound
+	for _, count := range counts {
+		pages[count.ParentID] = &Page[domain.Comment]{
+			Items: []domain.Comment{},
+			Total: count.Total,
+		}
 	}
 
-	return comments, nil
+	ranked := visible.Select("*, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY created_at, id) AS position")
+
+	var replies []domain.Comment
+	if err := r.db.Table("(?) AS comments", ranked).Where("position <= ?", limit).Order("created_at, id").Preload("User").Find(&replies).Error; err != nil {
+		return nil, err
+	}
+
+	for _, reply := range replies {
+		page, ok := pages[*reply.ParentID]
+		if !ok {
+			continue
+		}
+		page.Items = append(page.Items, reply)
+	}
+
+	for _, page := range pages {
+		if int64(len(page.Items)) < page.Total && len(page.Items) > 0 {
+			last := page.Items[len(page.Items)-1]
+			page.NextCursor = encodeCursor(last.CreatedAt, last.ID)
+		}
+	}
+
+	return pages, nil
+}
+
+// CountReplies counts the direct replies of a comment, whatever their
+// visibility.
+func (r *commentRepository) CountReplies(id uint) (int64, error) {
+	var count int64
+	if err := r.db.Model(&domain.Comment{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
 }
 
 func (r *commentRepository) FindByID(id uint) (*domain.Comment, error) {
@@ -61,3 +162,73 @@ func (r *commentRepository) Update(comment *domain.Comment) (*domain.Comment, er
 func (r *commentRepository) Delete(comment *domain.Comment) error {
 	return r.db.Delete(&comment).Error
 }
+
+// FindDeleted returns a soft deleted comment, or a placeholder whose content
+// was not erased yet.
+func (r *commentRepository) FindDeleted(id uint) (*domain.Comment, error) {
+	var comment domain.Comment
+	if err := r.db.Unscoped().Preload("User").
+		Where("deleted_at IS NOT NULL OR (deleted = ? AND content <> ?)", true, domain.DeletedCommentContent).
+		First(&comment, id).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Restore brings back a soft deleted comment or placeholder, recording log
+// in the same transaction unless it is nil.
+func (r *commentRepository) Restore(comment *domain.Comment, log *domain.ModerationLog) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&comment).Updates(map[string]any{"deleted_at": nil, "deleted": false}).Error; err != nil {
+			return err
+		}
+		return createLog(tx, log)
+	})
+	if err != nil {
+		return err
+	}
+	comment.DeletedAt = gorm.DeletedAt{}
+	comment.Deleted = false
+	return nil
+}
+
+// Purge permanently deletes the comments soft deleted before the given time
+// with their reactions, and erases the content of the placeholders left
+// before then. It returns how many comments were deleted.
+func (r *commentRepository) Purge(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if purged, err = purgeComments(tx, tx.Unscoped().Model(&domain.Comment{}).Select("id").Where("deleted_at < ?", before)); err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.Comment{}).
+			Where("deleted = ? AND updated_at < ? AND content <> ?", true, before, domain.DeletedCommentContent).
+			UpdateColumn("content", domain.DeletedCommentContent).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// purgeComments permanently deletes the comments selected by the ids
+// subquery with their reactions.
+func purgeComments(tx *gorm.DB, ids *gorm.DB) (int64, error) {
+	if err := tx.Where("target_type = ? AND target_id IN (?)", domain.CommentTarget, ids).Delete(&domain.Reaction{}).Error; err != nil {
+		return 0, err
+	}
+
+	result := tx.Unscoped().Where("id IN (?)", ids).Delete(&domain.Comment{})
+	return result.RowsAffected, result.Error
+}
+
+var commentSortable = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+func commentKey(comment domain.Comment) (time.Time, uint) {
+	return comment.CreatedAt, comment.ID
+}
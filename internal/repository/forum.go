@@ -1,19 +1,32 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/aternity/zense/internal/entity/domain"
 	"gorm.io/gorm"
 )
 
 type ForumRepository interface {
 	Create(forum *domain.Forum) (*domain.Forum, error)
-	FindAll() ([]domain.Forum, error)
+	FindAll(filter ForumFilter, pagination Pagination) (*Page[domain.Forum], error)
 	FindByID(id uint) (*domain.Forum, error)
 	Update(forum *domain.Forum) (*domain.Forum, error)
 	Delete(forum *domain.Forum) error
+	FindDeleted(id uint) (*domain.Forum, error)
+	Restore(forum *domain.Forum, log *domain.ModerationLog) error
+	Purge(before time.Time) (int64, error)
 	RemoveTopic(forum *domain.Forum) error
 }
 
+type ForumFilter struct {
+	TopicID uint
+	UserID  uint
+	// ExcludeAnonymous leaves out anonymous forums, so filtering by author
+	// does not reveal who wrote them.
+	ExcludeAnonymous bool
+}
+
 type forumRepository struct {
 	db *gorm.DB
 }
@@ -31,17 +44,27 @@ func (r *forumRepository) Create(forum *domain.Forum) (*domain.Forum, error) {
 	return forum, nil
 }
 
-func (r *forumRepository) FindAll() ([]domain.Forum, error) {
-	var forums []domain.Forum
-	if err := r.db.Preload("User").Preload("Topics").Find(&forums).Error; err != nil {
-		return nil, err
+func (r *forumRepository) FindAll(filter ForumFilter, pagination Pagination) (*Page[domain.Forum], error) {
+	query := r.db.Model(&domain.Forum{})
+	if filter.TopicID != 0 {
+		query = query.Where("id IN (?)", r.db.Table("forum_topics").Select("forum_id").Where("topic_id = ?", filter.TopicID))
+	}
+	if filter.UserID != 0 {
+		query = query.Where("user_id = ?", filter.UserID)
+	}
+	if filter.ExcludeAnonymous {
+		query = query.Where("anonymous = ?", false)
 	}
 
-	if len(forums) == 0 {
-		return nil, gorm.ErrRecordNotFound
+	sortable := map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"title":      "title",
 	}
 
-	return forums, nil
+	return paginate(query, pagination, sortable, func(forum domain.Forum) (time.Time, uint) {
+		return forum.CreatedAt, forum.ID
+	}, "User", "Topics")
 }
 
 func (r *forumRepository) FindByID(id uint) (*domain.Forum, error) {
@@ -59,10 +82,82 @@ func (r *forumRepository) Update(forum *domain.Forum) (*domain.Forum, error) {
 	return forum, nil
 }
 
+// Delete soft deletes a forum along with the comments of its thread, all at
+// the same time so Restore can tell them from the comments deleted before.
 func (r *forumRepository) Delete(forum *domain.Forum) error {
-	return r.db.Select("Topics").Delete(&forum).Error
+	deletedAt := time.Now()
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Comment{}).Where("forum_id = ?", forum.ID).UpdateColumn("deleted_at", deletedAt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&forum).UpdateColumn("deleted_at", deletedAt).Error
+	})
+}
+
+// FindDeleted returns a soft deleted forum.
+func (r *forumRepository) FindDeleted(id uint) (*domain.Forum, error) {
+	var forum domain.Forum
+	if err := r.db.Unscoped().Preload("User").Preload("Topics").Where("deleted_at IS NOT NULL").First(&forum, id).Error; err != nil {
+		return nil, err
+	}
+	return &forum, nil
+}
+
+// Restore brings back a soft deleted forum and the comments deleted with it,
+// recording log in the same transaction unless it is nil.
+func (r *forumRepository) Restore(forum *domain.Forum, log *domain.ModerationLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&domain.Comment{}).
+			Where("forum_id = ? AND deleted_at = ?", forum.ID, forum.DeletedAt).
+			UpdateColumn("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&forum).UpdateColumn("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := createLog(tx, log); err != nil {
+			return err
+		}
+		forum.DeletedAt = gorm.DeletedAt{}
+		return nil
+	})
+}
+
+// Purge permanently deletes the forums soft deleted before the given time
+// and returns how many were deleted.
+func (r *forumRepository) Purge(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		purged, err = purgeForums(tx, tx.Unscoped().Model(&domain.Forum{}).Select("id").Where("deleted_at < ?", before))
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
 }
 
 func (r *forumRepository) RemoveTopic(forum *domain.Forum) error {
 	return r.db.Unscoped().Model(&forum).Association("Topics").Unscoped().Clear()
 }
+
+// purgeForums permanently deletes the forums selected by the ids subquery,
+// with their comments, topics and reactions.
+func purgeForums(tx *gorm.DB, ids *gorm.DB) (int64, error) {
+	comments := tx.Unscoped().Model(&domain.Comment{}).Select("id").Where("forum_id IN (?)", ids)
+	if err := tx.Where("(target_type = ? AND target_id IN (?)) OR (target_type = ? AND target_id IN (?))",
+		domain.ForumTarget, ids, domain.CommentTarget, comments).
+		Delete(&domain.Reaction{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Unscoped().Where("forum_id IN (?)", ids).Delete(&domain.Comment{}).Error; err != nil {
+		return 0, err
+	}
+	if err := tx.Exec("DELETE FROM forum_topics WHERE forum_id IN (?)", ids).Error; err != nil {
+		return 0, err
+	}
+
+	result := tx.Unscoped().Where("id IN (?)", ids).Delete(&domain.Forum{})
+	return result.RowsAffected, result.Error
+}
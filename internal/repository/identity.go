@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdentityRepository interface {
+	Create(identity *domain.Identity) (*domain.Identity, error)
+	FindBySubject(provider string, subject string) (*domain.Identity, error)
+	FindByUser(userID uint) ([]domain.Identity, error)
+	FindByID(id uint) (*domain.Identity, error)
+	Delete(id uint) error
+	CreateState(state *domain.OIDCState) (*domain.OIDCState, error)
+	UseState(hash string, provider string) (*domain.OIDCState, error)
+	DeleteExpiredStates(now time.Time) error
+}
+
+type identityRepository struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepository(db *gorm.DB) IdentityRepository {
+	return &identityRepository{
+		db: db,
+	}
+}
+
+func (r *identityRepository) Create(identity *domain.Identity) (*domain.Identity, error) {
+	if err := r.db.Create(&identity).Error; err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (r *identityRepository) FindBySubject(provider string, subject string) (*domain.Identity, error) {
+	var identity domain.Identity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *identityRepository) FindByUser(userID uint) ([]domain.Identity, error) {
+	var identities []domain.Identity
+	if err := r.db.Where("user_id = ?", userID).Order("created_at").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (r *identityRepository) FindByID(id uint) (*domain.Identity, error) {
+	var identity domain.Identity
+	if err := r.db.First(&identity, id).Error; err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *identityRepository) Delete(id uint) error {
+	return r.db.Delete(&domain.Identity{}, id).Error
+}
+
+func (r *identityRepository) CreateState(state *domain.OIDCState) (*domain.OIDCState, error) {
+	if err := r.db.Create(&state).Error; err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+// UseState removes the state with the hash and returns it, so a state only
+// completes one sign in. It returns gorm.ErrRecordNotFound when there is no
+// such state for the provider or it has expired.
+func (r *identityRepository) UseState(hash string, provider string) (*domain.OIDCState, error) {
+	var states []domain.OIDCState
+	result := r.db.Clauses(clause.Returning{}).
+		Where("hash = ? AND provider = ? AND expires_at > ?", hash, provider, time.Now()).
+		Delete(&states)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if len(states) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &states[0], nil
+}
+
+func (r *identityRepository) DeleteExpiredStates(now time.Time) error {
+	return r.db.Where("expires_at < ?", now).Delete(&domain.OIDCState{}).Error
+}
@@ -1,16 +1,99 @@
 package repository
 
 import (
+	"slices"
+	"time"
+
 	"github.com/aternity/zense/internal/entity/domain"
 	"gorm.io/gorm"
 )
 
 type JournalRepository interface {
 	Create(journal *domain.Journal) (*domain.Journal, error)
-	FindAll() ([]domain.Journal, error)
+	FindAll(userID uint, filter JournalFilter, pagination Pagination) (*Page[domain.Journal], error)
+	FindByUser(ownerID uint, userID uint, filter JournalFilter, pagination Pagination) (*Page[domain.Journal], error)
 	FindByID(id uint) (*domain.Journal, error)
 	Update(journal *domain.Journal) (*domain.Journal, error)
 	Delete(journal *domain.Journal) error
+	FindDeleted(id uint) (*domain.Journal, error)
+	Restore(journal *domain.Journal, log *domain.ModerationLog) error
+	Purge(before time.Time) (int64, error)
+	CountMoods(moods MoodRange, period string) ([]MoodCount, error)
+	ScoreMoods(moods MoodRange) ([]MoodScore, error)
+	CountMoodsByWeekday(moods MoodRange) ([]WeekdayMood, error)
+	FindStreaks(userID uint, timezone string) (*MoodStreaks, error)
+	Each(userID uint, fn func(journal domain.Journal) error) error
+	Import(userID uint, journals []domain.Journal) (int, error)
+}
+
+// JournalFilter narrows journal listings, zero values are ignored. To is
+// exclusive.
+type JournalFilter struct {
+	Mood domain.JournalMood
+	From time.Time
+	To   time.Time
+}
+
+// MoodRange selects the journals of a user written between From and To, To
+// being exclusive. Days, weeks and months start at midnight in Timezone.
+type MoodRange struct {
+	UserID   uint
+	From     time.Time
+	To       time.Time
+	Timezone string
+}
+
+// MoodCount is the number of journals written with one mood in the period
+// starting at Period.
+type MoodCount struct {
+	Period time.Time
+	Mood   domain.JournalMood
+	Total  int64
+}
+
+// MoodScore is the average mood of the journals written on Day, from 1 for
+// angry to 5 for happy.
+type MoodScore struct {
+	Day     time.Time
+	Score   float64
+	Entries int64
+}
+
+// WeekdayMood is the most frequent mood on an ISO weekday, 1 being Monday.
+type WeekdayMood struct {
+	Weekday int
+	Mood    domain.JournalMood
+	Total   int64
+}
+
+// MoodStreak is a run of consecutive days with at least one journal.
+type MoodStreak struct {
+	FirstDay time.Time
+	LastDay  time.Time
+	Days     int
+}
+
+type MoodStreaks struct {
+	Longest MoodStreak
+	Latest  MoodStreak
+}
+
+// importBatchSize is the number of imported journals checked and created per
+// query.
+const importBatchSize = 500
+
+// importKey identifies an imported journal among the journals of a user.
+// Times are compared in microseconds, the precision PostgreSQL stores.
+type importKey struct {
+	createdAt int64
+	content   string
+}
+
+// moodPeriods are the date_trunc fields CountMoods buckets by.
+var moodPeriods = map[string]bool{
+	"day":   true,
+	"week":  true,
+	"month": true,
 }
 
 type journalRepository struct {
@@ -30,17 +113,16 @@ func (r *journalRepository) Create(journal *domain.Journal) (*domain.Journal, er
 	return journal, nil
 }
 
-func (r *journalRepository) FindAll() ([]domain.Journal, error) {
-	var journals []domain.Journal
-	if err := r.db.Preload("User").Find(&journals).Error; err != nil {
-		return nil, err
-	}
-
-	if len(journals) == 0 {
-		return nil, gorm.ErrRecordNotFound
-	}
+// FindAll returns the public journals and every journal written by userID.
+func (r *journalRepository) FindAll(userID uint, filter JournalFilter, pagination Pagination) (*Page[domain.Journal], error) {
+	query := r.db.Model(&domain.Journal{}).Where("visibility = ? OR user_id = ?", domain.PublicJournal, userID)
+	return r.paginate(query, filter, pagination)
+}
 
-	return journals, nil
+// FindByUser returns the journals of ownerID that userID is allowed to read.
+func (r *journalRepository) FindByUser(ownerID uint, userID uint, filter JournalFilter, pagination Pagination) (*Page[domain.Journal], error) {
+	query := r.db.Model(&domain.Journal{}).Where("user_id = ? AND (visibility = ? OR user_id = ?)", ownerID, domain.PublicJournal, userID)
+	return r.paginate(query, filter, pagination)
 }
 
 func (r *journalRepository) FindByID(id uint) (*domain.Journal, error) {
@@ -61,3 +143,237 @@ func (r *journalRepository) Update(journal *domain.Journal) (*domain.Journal, er
 func (r *journalRepository) Delete(journal *domain.Journal) error {
 	return r.db.Delete(&journal).Error
 }
+
+// FindDeleted returns a soft deleted journal.
+func (r *journalRepository) FindDeleted(id uint) (*domain.Journal, error) {
+	var journal domain.Journal
+	if err := r.db.Unscoped().Preload("User").Where("deleted_at IS NOT NULL").First(&journal, id).Error; err != nil {
+		return nil, err
+	}
+	return &journal, nil
+}
+
+// Restore brings back a soft deleted journal, recording log in the same
+// transaction unless it is nil.
+func (r *journalRepository) Restore(journal *domain.Journal, log *domain.ModerationLog) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&journal).UpdateColumn("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return createLog(tx, log)
+	})
+	if err != nil {
+		return err
+	}
+	journal.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+// Purge permanently deletes the journals soft deleted before the given time
+// with their reactions, and returns how many were deleted.
+func (r *journalRepository) Purge(before time.Time) (int64, error) {
+	var purged int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		journals := tx.Unscoped().Model(&domain.Journal{}).Select("id").Where("deleted_at < ?", before)
+		if err := tx.Where("target_type = ? AND target_id IN (?)", domain.JournalTarget, journals).Delete(&domain.Reaction{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Unscoped().Where("deleted_at < ?", before).Delete(&domain.Journal{})
+		purged = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return purged, nil
+}
+
+// CountMoods counts the journals of every mood in each day, week or month of
+// the range.
+func (r *journalRepository) CountMoods(moods MoodRange, period string) ([]MoodCount, error) {
+	if !moodPeriods[period] {
+		period = "day"
+	}
+
+	counts := []MoodCount{}
+	if err := r.moods(moods).
+		Select("date_trunc(?, created_at AT TIME ZONE ?) AS period, mood, COUNT(*) AS total", period, moods.Timezone).
+		Group("1, 2").
+		Order("1, 2").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+// ScoreMoods averages the mood of the journals of every day of the range,
+// weighting each mood from 1 for angry to 5 for happy.
+func (r *journalRepository) ScoreMoods(moods MoodRange) ([]MoodScore, error) {
+	score := gorm.Expr("CASE mood WHEN ? THEN 5 WHEN ? THEN 4 WHEN ? THEN 3 WHEN ? THEN 2 WHEN ? THEN 1 END",
+		domain.Happy, domain.Good, domain.Normal, domain.Sad, domain.Angry)
+
+	scores := []MoodScore{}
+	if err := r.moods(moods).
+		Select("date_trunc('day', created_at AT TIME ZONE ?) AS day, AVG(?) AS score, COUNT(*) AS entries", moods.Timezone, score).
+		Group("day").
+		Order("day").
+		Scan(&scores).Error; err != nil {
+		return nil, err
+	}
+
+	return scores, nil
+}
+
+// CountMoodsByWeekday returns the most frequent mood of every weekday of the
+// range that has journals.
+func (r *journalRepository) CountMoodsByWeekday(moods MoodRange) ([]WeekdayMood, error) {
+	counts := r.moods(moods).
+		Select("EXTRACT(ISODOW FROM created_at AT TIME ZONE ?)::int AS weekday, mood, COUNT(*) AS total", moods.Timezone).
+		Group("1, 2")
+
+	weekdays := []WeekdayMood{}
+	if err := r.db.Table("(?) AS counts", counts).
+		Select("DISTINCT ON (weekday) weekday, mood, total").
+		Order("weekday, total DESC, mood").
+		Scan(&weekdays).Error; err != nil {
+		return nil, err
+	}
+
+	return weekdays, nil
+}
+
+// FindStreaks finds the longest and the latest runs of consecutive days on
+// which userID wrote a journal, over their whole history.
+func (r *journalRepository) FindStreaks(userID uint, timezone string) (*MoodStreaks, error) {
+	days := r.db.Model(&domain.Journal{}).
+		Select("DISTINCT (created_at AT TIME ZONE ?)::date AS day", timezone).
+		Where("user_id = ?", userID)
+
+	// Consecutive days share the same difference between the day and its
+	// position.
+	islands := r.db.Table("(?) AS days", days).
+		Select("day, day - (ROW_NUMBER() OVER (ORDER BY day))::int AS island")
+
+	streaks := r.db.Table("(?) AS islands", islands).
+		Select("MIN(day) AS first_day, MAX(day) AS last_day, COUNT(*) AS days").
+		Group("island").
+		Session(&gorm.Session{})
+
+	var result MoodStreaks
+	if err := streaks.Order("days DESC, last_day DESC").Limit(1).Scan(&result.Longest).Error; err != nil {
+		return nil, err
+	}
+	if err := streaks.Order("last_day DESC").Limit(1).Scan(&result.Latest).Error; err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Each calls fn with every journal of userID, oldest first, reading them one
+// row at a time.
+func (r *journalRepository) Each(userID uint, fn func(journal domain.Journal) error) error {
+	rows, err := r.db.Model(&domain.Journal{}).Where("user_id = ?", userID).Order("created_at, id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var journal domain.Journal
+		if err := r.db.ScanRows(rows, &journal); err != nil {
+			return err
+		}
+
+		if err := fn(journal); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// Import creates the journals of userID in a single transaction and returns
+// how many were created. A journal with the same creation time and content
+// as one the user already has is skipped, so importing a file twice is a
+// no-op.
+func (r *journalRepository) Import(userID uint, journals []domain.Journal) (int, error) {
+	created := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for batch := range slices.Chunk(journals, importBatchSize) {
+			createdAts := make([]time.Time, 0, len(batch))
+			for _, journal := range batch {
+				createdAts = append(createdAts, journal.CreatedAt)
+			}
+
+			var existing []domain.Journal
+			if err := tx.Select("created_at, content").
+				Where("user_id = ? AND created_at IN ?", userID, createdAts).
+				Find(&existing).Error; err != nil {
+				return err
+			}
+
+			seen := make(map[importKey]bool, len(existing))
+			for _, journal := range existing {
+				seen[importKey{journal.CreatedAt.UnixMicro(), journal.Content}] = true
+			}
+
+			journals := make([]domain.Journal, 0, len(batch))
+			for _, journal := range batch {
+				key := importKey{journal.CreatedAt.UnixMicro(), journal.Content}
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				journal.UserID = userID
+				journals = append(journals, journal)
+			}
+
+			if len(journals) == 0 {
+				continue
+			}
+
+			if err := tx.Create(&journals).Error; err != nil {
+				return err
+			}
+			created += len(journals)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+func (r *journalRepository) moods(moods MoodRange) *gorm.DB {
+	return r.db.Model(&domain.Journal{}).
+		Where("user_id = ? AND created_at >= ? AND created_at < ?", moods.UserID, moods.From, moods.To)
+}
+
+func (r *journalRepository) paginate(query *gorm.DB, filter JournalFilter, pagination Pagination) (*Page[domain.Journal], error) {
+	if filter.Mood != "" {
+		query = query.Where("mood = ?", filter.Mood)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	sortable := map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"mood":       "mood",
+	}
+
+	return paginate(query, pagination, sortable, func(journal domain.Journal) (time.Time, uint) {
+		return journal.CreatedAt, journal.ID
+	}, "User")
+}
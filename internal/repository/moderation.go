@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+)
+
+type ModerationLogRepository interface {
+	Create(log *domain.ModerationLog) (*domain.ModerationLog, error)
+	FindAll(pagination Pagination) (*Page[domain.ModerationLog], error)
+	ModerateComment(comment *domain.Comment, log *domain.ModerationLog) error
+}
+
+type moderationLogRepository struct {
+	db *gorm.DB
+}
+
+func NewModerationLogRepository(db *gorm.DB) ModerationLogRepository {
+	return &moderationLogRepository{
+		db: db,
+	}
+}
+
+func (r *moderationLogRepository) Create(log *domain.ModerationLog) (*domain.ModerationLog, error) {
+	if err := r.db.Create(&log).Error; err != nil {
+		return nil, err
+	}
+	return log, nil
+}
+
+func (r *moderationLogRepository) FindAll(pagination Pagination) (*Page[domain.ModerationLog], error) {
+	sortable := map[string]string{
+		"created_at": "created_at",
+	}
+
+	return paginate(r.db.Model(&domain.ModerationLog{}), pagination, sortable, func(log domain.ModerationLog) (time.Time, uint) {
+		return log.CreatedAt, log.ID
+	})
+}
+
+// ModerateComment moves a comment out of review and records the decision in
+// the same transaction. It returns gorm.ErrRecordNotFound when the comment is
+// no longer waiting for review.
+func (r *moderationLogRepository) ModerateComment(comment *domain.Comment, log *domain.ModerationLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Comment{}).
+			Where("id = ? AND visibility = ?", comment.ID, domain.ReviewComment).
+			Update("visibility", comment.Visibility)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(&log).Error
+	})
+}
+
+// createLog records log in tx, nothing when it is nil.
+func createLog(tx *gorm.DB, log *domain.ModerationLog) error {
+	if log == nil {
+		return nil
+	}
+	return tx.Create(log).Error
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Pagination selects one page of a list. A positive Page switches to offset
+// pagination, otherwise the list is walked with keyset cursors on
+// (created_at, id). Cursors are only issued for the created_at sort, any
+// other sort field falls back to offset pages.
+type Pagination struct {
+	Cursor string
+	Page   int
+	Limit  int
+	Sort   string
+	Order  string
+}
+
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	Total      int64
+}
+
+// paginate counts the rows matched by query and loads the requested page.
+// sortable maps the public sort names to their columns, key reads the
+// keyset of an item to build the next cursor.
+func paginate[T any](query *gorm.DB, p Pagination, sortable map[string]string, key func(T) (time.Time, uint), preloads ...string) (*Page[T], error) {
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	column, ok := sortable[p.Sort]
+	if !ok {
+		column = "created_at"
+	}
+
+	direction, operator := "DESC", "<"
+	if strings.EqualFold(p.Order, "asc") {
+		direction, operator = "ASC", ">"
+	}
+
+	limit := p.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	find := query
+	for _, preload := range preloads {
+		find = find.Preload(preload)
+	}
+	find = find.Order(column + " " + direction).Order("id " + direction)
+
+	keyset := p.Page <= 0 && column == "created_at"
+	if keyset {
+		if p.Cursor != "" {
+			createdAt, id, err := decodeCursor(p.Cursor)
+			if err != nil {
+				return nil, err
+			}
+			find = find.Where("(created_at, id) "+operator+" (?, ?)", createdAt, id)
+		}
+		// One extra row tells whether there is a next page.
+		find = find.Limit(limit + 1)
+	} else {
+		page := max(p.Page, 1)
+		find = find.Offset((page - 1) * limit).Limit(limit)
+	}
+
+	items := []T{}
+	if err := find.Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	result := &Page[T]{
+		Items: items,
+		Total: total,
+	}
+
+	if keyset && len(items) > limit {
+		result.Items = items[:limit]
+		result.NextCursor = encodeCursor(key(items[limit-1]))
+	}
+
+	return result, nil
+}
+
+func encodeCursor(createdAt time.Time, id uint) string {
+	return base64.RawURLEncoding.EncodeToString(fmt.Appendf(nil, "%d:%d", createdAt.UnixNano(), id))
+}
+
+func decodeCursor(cursor string) (time.Time, uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	var nanos int64
+	var id uint
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &nanos, &id); err != nil {
+		return time.Time{}, 0, ErrInvalidCursor
+	}
+
+	return time.Unix(0, nanos), id, nil
+}
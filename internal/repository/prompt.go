@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+)
+
+type PromptTemplateRepository interface {
+	FindActive() ([]domain.PromptTemplate, error)
+}
+
+type promptTemplateRepository struct {
+	db *gorm.DB
+}
+
+func NewPromptTemplateRepository(db *gorm.DB) PromptTemplateRepository {
+	return &promptTemplateRepository{
+		db: db,
+	}
+}
+
+func (r *promptTemplateRepository) FindActive() ([]domain.PromptTemplate, error) {
+	var templates []domain.PromptTemplate
+	if err := r.db.Where("active = ?", true).Find(&templates).Error; err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
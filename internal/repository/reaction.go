@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReactionRepository interface {
+	Create(reaction *domain.Reaction) (*domain.Reaction, error)
+	Delete(reaction *domain.Reaction) error
+	DeleteByTarget(targetType domain.ReactionTarget, targetID uint) error
+	CountByTargets(targetType domain.ReactionTarget, targetIDs []uint) ([]ReactionCount, error)
+	FindByUser(userID uint, targetType domain.ReactionTarget, targetIDs []uint) ([]domain.Reaction, error)
+}
+
+type ReactionCount struct {
+	TargetID uint
+	Type     domain.ReactionType
+	Total    int64
+}
+
+type reactionRepository struct {
+	db *gorm.DB
+}
+
+func NewReactionRepository(db *gorm.DB) ReactionRepository {
+	return &reactionRepository{
+		db: db,
+	}
+}
+
+// Create leaves the reaction, reacting twice with the same type is a no-op.
+func (r *reactionRepository) Create(reaction *domain.Reaction) (*domain.Reaction, error) {
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction).Error; err != nil {
+		return nil, err
+	}
+	return reaction, nil
+}
+
+func (r *reactionRepository) Delete(reaction *domain.Reaction) error {
+	return r.db.Where("user_id = ? AND target_type = ? AND target_id = ? AND type = ?", reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Type).
+		Delete(&domain.Reaction{}).Error
+}
+
+func (r *reactionRepository) DeleteByTarget(targetType domain.ReactionTarget, targetID uint) error {
+	return r.db.Where("target_type = ? AND target_id = ?", targetType, targetID).Delete(&domain.Reaction{}).Error
+}
+
+func (r *reactionRepository) CountByTargets(targetType domain.ReactionTarget, targetIDs []uint) ([]ReactionCount, error) {
+	counts := []ReactionCount{}
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+
+	if err := r.db.Model(&domain.Reaction{}).
+		Select("target_id, type, COUNT(*) AS total").
+		Where("target_type = ? AND target_id IN ?", targetType, targetIDs).
+		Group("target_id, type").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *reactionRepository) FindByUser(userID uint, targetType domain.ReactionTarget, targetIDs []uint) ([]domain.Reaction, error) {
+	reactions := []domain.Reaction{}
+	if userID == 0 || len(targetIDs) == 0 {
+		return reactions, nil
+	}
+
+	if err := r.db.Where("user_id = ? AND target_type = ? AND target_id IN ?", userID, targetType, targetIDs).
+		Order("created_at").
+		Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
+}
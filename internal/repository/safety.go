@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+)
+
+type SafetyEventRepository interface {
+	Create(event *domain.SafetyEvent) (*domain.SafetyEvent, error)
+}
+
+type safetyEventRepository struct {
+	db *gorm.DB
+}
+
+func NewSafetyEventRepository(db *gorm.DB) SafetyEventRepository {
+	return &safetyEventRepository{
+		db: db,
+	}
+}
+
+func (r *safetyEventRepository) Create(event *domain.SafetyEvent) (*domain.SafetyEvent, error) {
+	if err := r.db.Create(&event).Error; err != nil {
+		return nil, err
+	}
+	return event, nil
+}
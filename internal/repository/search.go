@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"html"
+	"strings"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+)
+
+const (
+	ForumResult   = "forum"
+	JournalResult = "journal"
+	TopicResult   = "topic"
+)
+
+// searchLanguages maps every search language to its tsvector column and text
+// search configuration, both created by the search migration.
+var searchLanguages = map[string]struct {
+	column string
+	config string
+}{
+	"english":    {"tsv_english", "english"},
+	"indonesian": {"tsv_indonesian", "zense_indonesian"},
+}
+
+// Matches are highlighted between sentinels, taken out of the content first,
+// and only turned into <mark> tags once the snippet is HTML escaped, so the
+// content cannot smuggle markup in.
+const (
+	highlightStart = "\ue000"
+	highlightStop  = "\ue001"
+)
+
+const headlineOptions = `StartSel="` + highlightStart + `", StopSel="` + highlightStop + `", MaxWords=35, MinWords=15, MaxFragments=2`
+
+var highlighter = strings.NewReplacer(highlightStart, "<mark>", highlightStop, "</mark>")
+
+type SearchRepository interface {
+	Search(filter SearchFilter, pagination Pagination) (*Page[SearchResult], error)
+}
+
+// SearchFilter narrows a search. Type keeps one kind of result, TopicID keeps
+// the forums of a topic only.
+type SearchFilter struct {
+	Query    string
+	Language string
+	Type     string
+	TopicID  uint
+}
+
+type SearchResult struct {
+	Type      string
+	ID        uint
+	Title     string
+	Snippet   string
+	Rank      float64
+	CreatedAt time.Time
+}
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{
+		db: db,
+	}
+}
+
+// Search ranks the forums, public journals and topics matching the query,
+// best matches first. Results are paged by offset only since ranks are not
+// stable enough for cursors.
+func (r *searchRepository) Search(filter SearchFilter, pagination Pagination) (*Page[SearchResult], error) {
+	language, ok := searchLanguages[filter.Language]
+	if !ok {
+		language = searchLanguages["english"]
+	}
+
+	query := gorm.Expr("websearch_to_tsquery(?::regconfig, ?)", language.config, filter.Query)
+	rank := gorm.Expr("ts_rank("+language.column+", ?)", query)
+	match := language.column + " @@ ?"
+
+	var sources []any
+	if filter.Type == "" || filter.Type == ForumResult {
+		forums := r.db.Model(&domain.Forum{}).
+			Select("? AS type, id, title, content AS body, ? AS rank, created_at", ForumResult, rank).
+			Where(match, query)
+		if filter.TopicID != 0 {
+			forums = forums.Where("id IN (?)", r.db.Table("forum_topics").Select("forum_id").Where("topic_id = ?", filter.TopicID))
+		}
+		sources = append(sources, forums)
+	}
+	if filter.TopicID == 0 && (filter.Type == "" || filter.Type == JournalResult) {
+		sources = append(sources, r.db.Model(&domain.Journal{}).
+			Select("? AS type, id, '' AS title, content AS body, ? AS rank, created_at", JournalResult, rank).
+			Where("visibility = ?", domain.PublicJournal).
+			Where(match, query))
+	}
+	if filter.TopicID == 0 && (filter.Type == "" || filter.Type == TopicResult) {
+		sources = append(sources, r.db.Model(&domain.Topic{}).
+			Select("? AS type, id, name AS title, description AS body, ? AS rank, created_at", TopicResult, rank).
+			Where(match, query))
+	}
+
+	result := &Page[SearchResult]{
+		Items: []SearchResult{},
+	}
+	if len(sources) == 0 {
+		return result, nil
+	}
+
+	union := "?"
+	for range sources[1:] {
+		union += " UNION ALL ?"
+	}
+	results := r.db.Table("("+union+") AS results", sources...).Session(&gorm.Session{})
+
+	if err := results.Count(&result.Total).Error; err != nil {
+		return nil, err
+	}
+
+	limit := pagination.Limit
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	page := max(pagination.Page, 1)
+
+	order := "rank DESC, created_at DESC, id DESC"
+	ranked := results.Order(order).Offset((page - 1) * limit).Limit(limit)
+
+	// Snippets are only highlighted for the rows of the page.
+	if err := r.db.Table("(?) AS results", ranked).
+		Select("type, id, title, ts_headline(?::regconfig, translate(body, ?, ''), ?, ?) AS snippet, rank, created_at",
+			language.config, highlightStart+highlightStop, query, headlineOptions).
+		Order(order).
+		Scan(&result.Items).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range result.Items {
+		result.Items[i].Snippet = highlighter.Replace(html.EscapeString(result.Items[i].Snippet))
+	}
+
+	return result, nil
+}
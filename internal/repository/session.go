@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionRepository interface {
+	Create(token *domain.RefreshToken) (*domain.RefreshToken, error)
+	FindByHash(hash string) (*domain.RefreshToken, error)
+	Rotate(token *domain.RefreshToken, next *domain.RefreshToken) (*domain.RefreshToken, error)
+	RevokeSession(sessionID string) error
+	RevokeUser(userID uint) error
+	RevokeOthers(userID uint, sessionID string) error
+	Deny(token *domain.RevokedToken) error
+	IsDenied(id string) (bool, error)
+	DeleteExpired(now time.Time) error
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (r *sessionRepository) Create(token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	if err := r.db.Create(&token).Error; err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *sessionRepository) FindByHash(hash string) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	if err := r.db.Where("hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Rotate marks token as used and creates next in its place. It returns
+// gorm.ErrRecordNotFound when token was already used or revoked, so a token
+// refreshed twice at once is only rotated once.
+func (r *sessionRepository) Rotate(token *domain.RefreshToken, next *domain.RefreshToken) (*domain.RefreshToken, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.RefreshToken{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", token.ID).
+			Update("rotated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(&next).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// RevokeSession revokes the refresh tokens of a session and denies the access
+// tokens issued with them.
+func (r *sessionRepository) RevokeSession(sessionID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return revokeTokens(tx, "session_id = ?", sessionID)
+	})
+}
+
+// RevokeUser revokes every session of a user.
+func (r *sessionRepository) RevokeUser(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return revokeTokens(tx, "user_id = ?", userID)
+	})
+}
+
+// RevokeOthers revokes every session of a user but sessionID.
+func (r *sessionRepository) RevokeOthers(userID uint, sessionID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return revokeTokens(tx, "user_id = ? AND session_id <> ?", userID, sessionID)
+	})
+}
+
+func (r *sessionRepository) Deny(token *domain.RevokedToken) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&token).Error
+}
+
+func (r *sessionRepository) IsDenied(id string) (bool, error) {
+	var count int64
+	if err := r.db.Model(&domain.RevokedToken{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DeleteExpired removes the refresh tokens and denied access tokens that
+// expired before now, they cannot be used anymore anyway.
+func (r *sessionRepository) DeleteExpired(now time.Time) error {
+	if err := r.db.Where("expires_at < ?", now).Delete(&domain.RefreshToken{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("expires_at < ?", now).Delete(&domain.RevokedToken{}).Error
+}
+
+// revokeTokens revokes the refresh tokens matching the condition and denies
+// the access tokens issued with them that did not expire yet.
+func revokeTokens(tx *gorm.DB, query string, args ...any) error {
+	now := time.Now()
+
+	var tokens []domain.RefreshToken
+	if err := tx.Where(query, args...).Where("access_expires_at > ?", now).Find(&tokens).Error; err != nil {
+		return err
+	}
+
+	if len(tokens) > 0 {
+		denied := make([]domain.RevokedToken, 0, len(tokens))
+		for _, token := range tokens {
+			denied = append(denied, domain.RevokedToken{
+				ID:        token.AccessTokenID,
+				ExpiresAt: token.AccessExpiresAt,
+			})
+		}
+
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&denied).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&domain.RefreshToken{}).Where(query, args...).Where("revoked_at IS NULL").Update("revoked_at", now).Error
+}
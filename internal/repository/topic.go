@@ -1,18 +1,24 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/aternity/zense/internal/entity/domain"
 	"gorm.io/gorm"
 )
 
 type TopicRepository interface {
 	Create(topic *domain.Topic) (*domain.Topic, error)
-	FindAll() ([]domain.Topic, error)
+	FindAll(filter TopicFilter, pagination Pagination) (*Page[domain.Topic], error)
 	FindByID(id uint) (*domain.Topic, error)
 	Update(topic *domain.Topic) (*domain.Topic, error)
 	Delete(topic *domain.Topic) error
 }
 
+type TopicFilter struct {
+	Name string
+}
+
 type topicRepository struct {
 	db *gorm.DB
 }
@@ -30,17 +36,20 @@ func (r *topicRepository) Create(topic *domain.Topic) (*domain.Topic, error) {
 	return topic, nil
 }
 
-func (r *topicRepository) FindAll() ([]domain.Topic, error) {
-	var topics []domain.Topic
-	if err := r.db.Find(&topics).Error; err != nil {
-		return nil, err
+func (r *topicRepository) FindAll(filter TopicFilter, pagination Pagination) (*Page[domain.Topic], error) {
+	query := r.db.Model(&domain.Topic{})
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Name+"%")
 	}
 
-	if len(topics) == 0 {
-		return nil, gorm.ErrRecordNotFound
+	sortable := map[string]string{
+		"created_at": "created_at",
+		"name":       "name",
 	}
 
-	return topics, nil
+	return paginate(query, pagination, sortable, func(topic domain.Topic) (time.Time, uint) {
+		return topic.CreatedAt, topic.ID
+	})
 }
 
 func (r *topicRepository) FindByID(id uint) (*domain.Topic, error) {
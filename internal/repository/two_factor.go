@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TwoFactorRepository interface {
+	FindByUser(userID uint) (*domain.TwoFactor, error)
+	Save(twoFactor *domain.TwoFactor) (*domain.TwoFactor, error)
+	Enable(userID uint, codes []domain.RecoveryCode) error
+	ReplaceRecoveryCodes(userID uint, codes []domain.RecoveryCode) error
+	UseStep(userID uint, step int64) error
+	UseRecoveryCode(userID uint, hash string) error
+	Delete(userID uint) error
+}
+
+type twoFactorRepository struct {
+	db *gorm.DB
+}
+
+func NewTwoFactorRepository(db *gorm.DB) TwoFactorRepository {
+	return &twoFactorRepository{
+		db: db,
+	}
+}
+
+func (r *twoFactorRepository) FindByUser(userID uint) (*domain.TwoFactor, error) {
+	var twoFactor domain.TwoFactor
+	if err := r.db.Where("user_id = ?", userID).First(&twoFactor).Error; err != nil {
+		return nil, err
+	}
+	return &twoFactor, nil
+}
+
+// Save stores a pending secret, replacing the pending one of the user. It
+// returns gorm.ErrRecordNotFound when the user already enabled two-factor
+// authentication, the secret in use is never replaced.
+func (r *twoFactorRepository) Save(twoFactor *domain.TwoFactor) (*domain.TwoFactor, error) {
+	result := r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "last_step", "updated_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "two_factors.enabled_at IS NULL"}}},
+	}).Create(&twoFactor)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return twoFactor, nil
+}
+
+// Enable turns on the pending secret of the user with a first set of recovery
+// codes. It returns gorm.ErrRecordNotFound when there is no pending secret.
+func (r *twoFactorRepository) Enable(userID uint, codes []domain.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.TwoFactor{}).
+			Where("user_id = ? AND enabled_at IS NULL", userID).
+			Update("enabled_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return replaceRecoveryCodes(tx, userID, codes)
+	})
+}
+
+func (r *twoFactorRepository) ReplaceRecoveryCodes(userID uint, codes []domain.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return replaceRecoveryCodes(tx, userID, codes)
+	})
+}
+
+// UseStep records that the code of step was used. It returns
+// gorm.ErrRecordNotFound when a code of that step or a later one was used
+// already.
+func (r *twoFactorRepository) UseStep(userID uint, step int64) error {
+	result := r.db.Model(&domain.TwoFactor{}).
+		Where("user_id = ? AND last_step < ?", userID, step).
+		Update("last_step", step)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UseRecoveryCode marks the recovery code with the hash as used. It returns
+// gorm.ErrRecordNotFound when the user has no such unused code.
+func (r *twoFactorRepository) UseRecoveryCode(userID uint, hash string) error {
+	result := r.db.Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete turns two-factor authentication off, removing the secret and the
+// recovery codes of the user.
+func (r *twoFactorRepository) Delete(userID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.TwoFactor{}).Error
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codes []domain.RecoveryCode) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return tx.Create(&codes).Error
+}
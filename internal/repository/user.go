@@ -1,18 +1,24 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/aternity/zense/internal/entity/domain"
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
 	Create(user *domain.User) (*domain.User, error)
-	FindAll() ([]domain.User, error)
+	FindAll(filter UserFilter, pagination Pagination) (*Page[domain.User], error)
 	FindByID(id uint) (*domain.User, error)
 	FindByEmail(email string) (*domain.User, error)
 	Update(user *domain.User) (*domain.User, error)
-	Delete(user *domain.User) error
+	UpdateEmail(user *domain.User) (*domain.User, error)
+	SetVerified(id uint, verifiedAt *time.Time) error
+}
+
+type UserFilter struct {
+	Name string
 }
 
 type userRepository struct {
@@ -32,17 +38,20 @@ func (r *userRepository) Create(user *domain.User) (*domain.User, error) {
 	return user, nil
 }
 
-func (r *userRepository) FindAll() ([]domain.User, error) {
-	var users []domain.User
-	if err := r.db.Find(&users).Error; err != nil {
-		return nil, err
+func (r *userRepository) FindAll(filter UserFilter, pagination Pagination) (*Page[domain.User], error) {
+	query := r.db.Model(&domain.User{})
+	if filter.Name != "" {
+		query = query.Where("name ILIKE ?", "%"+filter.Name+"%")
 	}
 
-	if len(users) == 0 {
-		return nil, gorm.ErrRecordNotFound
+	sortable := map[string]string{
+		"created_at": "created_at",
+		"name":       "name",
 	}
 
-	return users, nil
+	return paginate(query, pagination, sortable, func(user domain.User) (time.Time, uint) {
+		return user.CreatedAt, user.ID
+	})
 }
 
 func (r *userRepository) FindByID(id uint) (*domain.User, error) {
@@ -68,6 +77,23 @@ func (r *userRepository) Update(user *domain.User) (*domain.User, error) {
 	return user, nil
 }
 
-func (r *userRepository) Delete(user *domain.User) error {
-	return r.db.Select(clause.Associations).Delete(&user).Error
+// UpdateEmail updates the user like Update and marks their new email
+// unverified in the same transaction.
+func (r *userRepository) UpdateEmail(user *domain.User) (*domain.User, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Updates(&user).Error; err != nil {
+			return err
+		}
+		return tx.Model(&domain.User{ID: user.ID}).Update("verified_at", nil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// SetVerified sets when the user verified their email, nil marks it
+// unverified again.
+func (r *userRepository) SetVerified(id uint, verifiedAt *time.Time) error {
+	return r.db.Model(&domain.User{ID: id}).Update("verified_at", verifiedAt).Error
 }
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+)
+
+type UserTokenRepository interface {
+	Create(token *domain.UserToken) (*domain.UserToken, error)
+	Use(hash string, purpose domain.UserTokenPurpose) (*domain.UserToken, error)
+	DeleteExpired(now time.Time) error
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{
+		db: db,
+	}
+}
+
+// Create stores token in place of the unused tokens the user has for the same
+// purpose, so only the latest email sent works.
+func (r *userTokenRepository) Create(token *domain.UserToken) (*domain.UserToken, error) {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Delete(&domain.UserToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&token).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Use marks the token with the hash as used and returns it. It returns
+// gorm.ErrRecordNotFound when there is no such token for the purpose, or it
+// was already used or has expired.
+func (r *userTokenRepository) Use(hash string, purpose domain.UserTokenPurpose) (*domain.UserToken, error) {
+	now := time.Now()
+
+	var token domain.UserToken
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("hash = ? AND purpose = ?", hash, purpose).First(&token).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&domain.UserToken{}).
+			Where("id = ? AND used_at IS NULL AND expires_at > ?", token.ID, now).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		token.UsedAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// DeleteExpired removes the tokens that expired before now, used or not.
+func (r *userTokenRepository) DeleteExpired(now time.Time) error {
+	return r.db.Where("expires_at < ?", now).Delete(&domain.UserToken{}).Error
+}
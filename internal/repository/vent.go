@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"github.com/aternity/zense/internal/entity/domain"
+	"gorm.io/gorm"
+)
+
+type VentReplyRepository interface {
+	Create(reply *domain.VentReply) (*domain.VentReply, error)
+}
+
+type ventReplyRepository struct {
+	db *gorm.DB
+}
+
+func NewVentReplyRepository(db *gorm.DB) VentReplyRepository {
+	return &ventReplyRepository{
+		db: db,
+	}
+}
+
+func (r *ventReplyRepository) Create(reply *domain.VentReply) (*domain.VentReply, error) {
+	if err := r.db.Create(&reply).Error; err != nil {
+		return nil, err
+	}
+	return reply, nil
+}
@@ -0,0 +1,62 @@
+package safety
+
+import (
+	"context"
+)
+
+type Level string
+type Category string
+type Stage string
+
+const (
+	NoRisk       Level = "none"
+	ModerateRisk Level = "moderate"
+	HighRisk     Level = "high"
+)
+
+const (
+	Suicide  Category = "suicide"
+	SelfHarm Category = "self_harm"
+	Violence Category = "violence"
+)
+
+const (
+	InputStage  Stage = "input"
+	OutputStage Stage = "output"
+)
+
+// Assessment is the outcome of screening a single message. It never carries
+// the message itself so it can be logged and stored safely.
+type Assessment struct {
+	Level    Level
+	Category Category
+	Rule     string
+}
+
+func (a Assessment) Flagged() bool {
+	return a.Level != "" && a.Level != NoRisk
+}
+
+// Classifier screens a message written in the given language.
+type Classifier interface {
+	Classify(ctx context.Context, language string, text string) (Assessment, error)
+}
+
+func (l Level) weight() int {
+	switch l {
+	case HighRisk:
+		return 2
+	case ModerateRisk:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Max returns whichever assessment carries the higher risk.
+func Max(a, b Assessment) Assessment {
+	if b.Level.weight() > a.Level.weight() {
+		return b
+	}
+	return a
+}
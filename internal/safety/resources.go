@@ -0,0 +1,67 @@
+package safety
+
+type Resource struct {
+	Name        string `json:"name"`
+	Phone       string `json:"phone,omitempty"`
+	URL         string `json:"url,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+var resources = map[string][]Resource{
+	"id": {
+		{
+			Name:        "Layanan SEJIWA Kementerian Kesehatan",
+			Phone:       "119 ext. 8",
+			Description: "Layanan konseling kesehatan jiwa gratis.",
+		},
+		{
+			Name:        "Layanan Darurat",
+			Phone:       "112",
+			Description: "Hubungi jika kamu atau orang di sekitarmu dalam bahaya.",
+		},
+		{
+			Name: "Find A Helpline",
+			URL:  "https://findahelpline.com",
+		},
+	},
+	"en": {
+		{
+			Name:        "988 Suicide & Crisis Lifeline (US)",
+			Phone:       "988",
+			Description: "Call or text, available 24/7.",
+		},
+		{
+			Name:        "Emergency services",
+			Phone:       "112",
+			Description: "Call if you or someone near you is in immediate danger.",
+		},
+		{
+			Name:        "Find A Helpline",
+			URL:         "https://findahelpline.com",
+			Description: "Free, confidential support lines in your country.",
+		},
+	},
+}
+
+var messages = map[string]string{
+	"id": "Aku sangat peduli dengan keselamatanmu. Apa yang kamu rasakan sekarang terdengar sangat berat, dan kamu tidak perlu menghadapinya sendirian. Tolong hubungi salah satu layanan di bawah ini atau orang yang kamu percaya sekarang juga.",
+	"en": "I really care about your safety. What you're feeling sounds very heavy, and you don't have to face it alone. Please reach out to one of the services below or someone you trust right now.",
+}
+
+// Resources returns the crisis hotlines for the given language, falling back
+// to the Indonesian list.
+func Resources(language string) []Resource {
+	if r, ok := resources[language]; ok {
+		return r
+	}
+	return resources["id"]
+}
+
+// Message returns the supportive reply sent instead of a model answer when a
+// conversation is considered high risk.
+func Message(language string) string {
+	if m, ok := messages[language]; ok {
+		return m
+	}
+	return messages["id"]
+}
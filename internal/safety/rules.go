@@ -0,0 +1,226 @@
+package safety
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+type Rule struct {
+	Name     string
+	Category Category
+	Level    Level
+	Pattern  *regexp.Regexp
+}
+
+type ruleConfig struct {
+	Name     string   `json:"name"`
+	Category Category `json:"category"`
+	Level    Level    `json:"level"`
+	Patterns []string `json:"patterns"`
+}
+
+type ruleClassifier struct {
+	rules    map[string][]Rule
+	fallback string
+}
+
+// NewRuleClassifier returns a classifier that matches messages against a set
+// of keyword and regular expression rules per language. Every message is also
+// screened with the fallback language since users often mix languages.
+func NewRuleClassifier(rules map[string][]Rule, fallback string) Classifier {
+	return &ruleClassifier{
+		rules:    rules,
+		fallback: fallback,
+	}
+}
+
+func (c *ruleClassifier) Classify(ctx context.Context, language string, text string) (Assessment, error) {
+	rules := c.rules[language]
+	if language != c.fallback {
+		rules = append(rules[:len(rules):len(rules)], c.rules[c.fallback]...)
+	}
+
+	assessment := Assessment{Level: NoRisk}
+	for _, rule := range rules {
+		if rule.Pattern.MatchString(text) {
+			assessment = Max(assessment, Assessment{
+				Level:    rule.Level,
+				Category: rule.Category,
+				Rule:     rule.Name,
+			})
+		}
+	}
+
+	return assessment, nil
+}
+
+// LoadRules reads a JSON ruleset keyed by language, for example
+// {"en": [{"name": "en-suicide", "category": "suicide", "level": "high", "patterns": ["kill myself"]}]}.
+// Patterns are case-insensitive regular expressions.
+func LoadRules(path string) (map[string][]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var config map[string][]ruleConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	rules := map[string][]Rule{}
+	for language, configs := range config {
+		for _, cfg := range configs {
+			rule, err := compileRule(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("safety rule %q: %w", cfg.Name, err)
+			}
+			rules[language] = append(rules[language], rule)
+		}
+	}
+
+	return rules, nil
+}
+
+// DefaultRules returns the built-in Indonesian and English rulesets.
+func DefaultRules() map[string][]Rule {
+	rules := map[string][]Rule{}
+	for language, configs := range defaultRules {
+		for _, cfg := range configs {
+			rules[language] = append(rules[language], mustCompileRule(cfg))
+		}
+	}
+	return rules
+}
+
+func compileRule(cfg ruleConfig) (Rule, error) {
+	if cfg.Level != HighRisk && cfg.Level != ModerateRisk {
+		return Rule{}, fmt.Errorf("unknown level %q", cfg.Level)
+	}
+
+	var pattern string
+	for i, p := range cfg.Patterns {
+		if i > 0 {
+			pattern += "|"
+		}
+		pattern += "(?:" + p + ")"
+	}
+
+	re, err := regexp.Compile(`(?i)` + pattern)
+	if err != nil {
+		return Rule{}, err
+	}
+
+	return Rule{
+		Name:     cfg.Name,
+		Category: cfg.Category,
+		Level:    cfg.Level,
+		Pattern:  re,
+	}, nil
+}
+
+func mustCompileRule(cfg ruleConfig) Rule {
+	rule, err := compileRule(cfg)
+	if err != nil {
+		panic(err)
+	}
+	return rule
+}
+
+var defaultRules = map[string][]ruleConfig{
+	"en": {
+		{
+			Name:     "en-suicide-intent",
+			Category: Suicide,
+			Level:    HighRisk,
+			Patterns: []string{
+				`\b(kill|end|take)\s+(myself|my\s+(own\s+)?life)\b`,
+				`\bsuicid(e|al)\b`,
+				`\bwant\s+to\s+die\b`,
+				`\b(don'?t|do\s+not)\s+want\s+to\s+(live|be\s+alive|wake\s+up)\b`,
+				`\bbetter\s+off\s+dead\b`,
+			},
+		},
+		{
+			Name:     "en-self-harm",
+			Category: SelfHarm,
+			Level:    HighRisk,
+			Patterns: []string{
+				`\b(cut|cutting|hurt|hurting|harm|harming|burn|burning)\s+myself\b`,
+				`\bself[\s-]?harm`,
+				`\boverdos(e|ing)\b`,
+			},
+		},
+		{
+			Name:     "en-hopelessness",
+			Category: Suicide,
+			Level:    ModerateRisk,
+			Patterns: []string{
+				`\bno\s+(reason|point)\s+(to|in)\s+(live|living|go(ing)?\s+on)\b`,
+				`\b(can'?t|cannot)\s+go\s+on\b`,
+				`\bdisappear\s+forever\b`,
+				`\beveryone\s+would\s+be\s+better\s+(off\s+)?without\s+me\b`,
+			},
+		},
+		{
+			Name:     "en-violence",
+			Category: Violence,
+			Level:    ModerateRisk,
+			Patterns: []string{
+				`\b(kill|hurt)\s+(him|her|them|someone|somebody)\b`,
+			},
+		},
+	},
+	"id": {
+		{
+			Name:     "id-suicide-intent",
+			Category: Suicide,
+			Level:    HighRisk,
+			Patterns: []string{
+				`\bbunuh\s+diri\b`,
+				`\bingin\s+mati\b`,
+				`\bmau\s+mati\b`,
+				`\bpengen\s+mati\b`,
+				`\bmengakhiri\s+hidup\b`,
+				`\bakhiri\s+hidup(ku)?\b`,
+				`\btidak\s+(ingin|mau)\s+hidup\s+lagi\b`,
+				`\bgak\s+mau\s+hidup\s+lagi\b`,
+			},
+		},
+		{
+			Name:     "id-self-harm",
+			Category: SelfHarm,
+			Level:    HighRisk,
+			Patterns: []string{
+				`\bmenyakiti\s+diri(ku|\s+sendiri)?\b`,
+				`\bmelukai\s+diri(ku|\s+sendiri)?\b`,
+				`\bmenyayat\s+(tangan|diri)\b`,
+				`\bself[\s-]?harm`,
+				`\boverdosis\b`,
+			},
+		},
+		{
+			Name:     "id-hopelessness",
+			Category: Suicide,
+			Level:    ModerateRisk,
+			Patterns: []string{
+				`\btidak\s+ada\s+(gunanya|alasan)\s+(untuk\s+)?hidup\b`,
+				`\bhidup\s+(ini\s+)?tidak\s+ada\s+artinya\b`,
+				`\bingin\s+menghilang\b`,
+				`\bsemua\s+orang\s+lebih\s+baik\s+tanpa\s+aku\b`,
+				`\bsudah\s+tidak\s+kuat\s+lagi\b`,
+			},
+		},
+		{
+			Name:     "id-violence",
+			Category: Violence,
+			Level:    ModerateRisk,
+			Patterns: []string{
+				`\b(bunuh|membunuh|melukai)\s+(dia|mereka|orang)\b`,
+			},
+		},
+	},
+}
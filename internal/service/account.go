@@ -0,0 +1,417 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/repository"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type AccountService interface {
+	CreateExport(req web.DataExportCreate) (*web.DataExportResponse, error)
+	FindExport(req web.DataExportFindByID) (*web.DataExportResponse, error)
+	DownloadExport(req web.DataExportFindByID) (string, error)
+	ScheduleDeletion(req web.AccountDeletionCreate) (*web.AccountDeletionResponse, error)
+	FindDeletion(req web.AccountDeletionFind) (*web.AccountDeletionResponse, error)
+	CancelDeletion(req web.AccountDeletionCancel) error
+	Run(ctx context.Context)
+}
+
+type AccountConfig struct {
+	// ExportDir is where the export archives are written.
+	ExportDir string
+	// ExportTTL is how long an archive can be downloaded once ready.
+	ExportTTL time.Duration
+	// DeletionGrace is how long a scheduled deletion can be cancelled.
+	DeletionGrace time.Duration
+	// WorkerInterval is how often the worker looks for due deletions and
+	// expired archives.
+	WorkerInterval time.Duration
+	// ReauthWindow is how recently users without a password or two-factor
+	// authentication must have logged in to delete their account, see
+	// reauthenticate.
+	ReauthWindow time.Duration
+}
+
+type accountService struct {
+	accountRepository   repository.AccountRepository
+	userRepository      repository.UserRepository
+	twoFactorRepository repository.TwoFactorRepository
+	config              AccountConfig
+	wake                chan struct{}
+}
+
+func NewAccountService(accountRepository repository.AccountRepository, userRepository repository.UserRepository, twoFactorRepository repository.TwoFactorRepository, config AccountConfig) AccountService {
+	return &accountService{
+		accountRepository:   accountRepository,
+		userRepository:      userRepository,
+		twoFactorRepository: twoFactorRepository,
+		config:              config,
+		wake:                make(chan struct{}, 1),
+	}
+}
+
+// CreateExport queues a data export, or returns the one the user is already
+// waiting for.
+func (s *accountService) CreateExport(req web.DataExportCreate) (*web.DataExportResponse, error) {
+	export, err := s.accountRepository.FindActiveExport(req.UserID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+
+		export, err = s.accountRepository.CreateExport(&domain.DataExport{
+			UserID: req.UserID,
+			Status: domain.PendingExport,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	return dataExportResponse(*export), nil
+}
+
+func (s *accountService) FindExport(req web.DataExportFindByID) (*web.DataExportResponse, error) {
+	export, err := s.findExport(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return dataExportResponse(*export), nil
+}
+
+// DownloadExport returns the path of the archive of a ready export.
+func (s *accountService) DownloadExport(req web.DataExportFindByID) (string, error) {
+	export, err := s.findExport(req)
+	if err != nil {
+		return "", err
+	}
+
+	if export.Status != domain.ReadyExport {
+		return "", echo.NewHTTPError(http.StatusConflict, "export is not ready")
+	}
+
+	return export.File, nil
+}
+
+// ScheduleDeletion confirms the password of the user and schedules the
+// erasure of their account once the grace period is over.
+func (s *accountService) ScheduleDeletion(req web.AccountDeletionCreate) (*web.AccountDeletionResponse, error) {
+	if req.ID != req.UserID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "you do not have permission to delete this user")
+	}
+
+	user, err := s.userRepository.FindByID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reauthenticate(s.twoFactorRepository, user, req.Password, req.Code, req.AuthenticatedAt, s.config.ReauthWindow); err != nil {
+		return nil, err
+	}
+
+	mode := req.Mode
+	if mode == "" {
+		mode = domain.AnonymizeErasure
+	}
+
+	deletion, err := s.accountRepository.SaveDeletion(&domain.AccountDeletion{
+		UserID:       user.ID,
+		Mode:         mode,
+		ScheduledFor: time.Now().Add(s.config.DeletionGrace),
+		CreatedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return accountDeletionResponse(*deletion), nil
+}
+
+func (s *accountService) FindDeletion(req web.AccountDeletionFind) (*web.AccountDeletionResponse, error) {
+	deletion, err := s.accountRepository.FindDeletion(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return accountDeletionResponse(*deletion), nil
+}
+
+func (s *accountService) CancelDeletion(req web.AccountDeletionCancel) error {
+	deletion, err := s.accountRepository.FindDeletion(req.UserID)
+	if err != nil {
+		return err
+	}
+
+	return s.accountRepository.DeleteDeletion(deletion)
+}
+
+// Run builds the queued exports, removes the expired archives and erases
+// the accounts whose grace period is over, until ctx is done.
+func (s *accountService) Run(ctx context.Context) {
+	if err := s.accountRepository.ResetRunningExports(); err != nil {
+		logrus.WithError(err).Error("failed to requeue interrupted data exports")
+	}
+
+	ticker := time.NewTicker(s.config.WorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		s.work()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+func (s *accountService) work() {
+	for {
+		export, err := s.accountRepository.ClaimExport()
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				logrus.WithError(err).Error("failed to claim a data export")
+			}
+			break
+		}
+
+		s.export(export)
+	}
+
+	expired, err := s.accountRepository.FindExpiredExports(time.Now())
+	if err != nil {
+		logrus.WithError(err).Error("failed to find expired data exports")
+	}
+
+	for _, export := range expired {
+		if err := s.removeExport(export); err != nil {
+			logrus.WithError(err).WithField("export_id", export.ID).Error("failed to remove an expired data export")
+		}
+	}
+
+	deletions, err := s.accountRepository.FindDueDeletions(time.Now())
+	if err != nil {
+		logrus.WithError(err).Error("failed to find due account deletions")
+	}
+
+	for _, deletion := range deletions {
+		if err := s.erase(deletion); err != nil {
+			logrus.WithError(err).WithField("user_id", deletion.UserID).Error("failed to erase an account")
+		}
+	}
+}
+
+// export writes the archive of a claimed export and marks it ready, or
+// failed with the reason.
+func (s *accountService) export(export *domain.DataExport) {
+	expiresAt := time.Now().Add(s.config.ExportTTL)
+	export.ExpiresAt = &expiresAt
+
+	file, err := s.writeArchive(export)
+	if err != nil {
+		logrus.WithError(err).WithField("export_id", export.ID).Error("failed to build a data export")
+		export.Status = domain.FailedExport
+		export.Error = "the archive could not be built, please request a new export"
+	} else {
+		export.Status = domain.ReadyExport
+		export.File = file
+	}
+
+	if _, err := s.accountRepository.UpdateExport(export); err != nil {
+		logrus.WithError(err).WithField("export_id", export.ID).Error("failed to save a data export")
+	}
+}
+
+// writeArchive bundles the profile, journals, forums, comments and reactions
+// of the user in a ZIP archive, one JSON file each.
+func (s *accountService) writeArchive(export *domain.DataExport) (path string, err error) {
+	data, err := s.accountRepository.FindData(export.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(s.config.ExportDir, 0o700); err != nil {
+		return "", err
+	}
+
+	path = filepath.Join(s.config.ExportDir, fmt.Sprintf("zense-export-%d-%d.zip", export.UserID, export.ID))
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(path)
+		}
+	}()
+
+	archive := zip.NewWriter(file)
+
+	journals := make([]web.JournalEntry, 0, len(data.Journals))
+	for _, journal := range data.Journals {
+		journals = append(journals, journalEntry(journal))
+	}
+
+	forums := make([]web.ForumResponse, 0, len(data.Forums))
+	for _, forum := range data.Forums {
+		var topics []web.TopicResponse
+		for _, topic := range forum.Topics {
+			topics = append(topics, web.TopicResponse{
+				ID:   topic.ID,
+				Name: topic.Name,
+			})
+		}
+
+		forums = append(forums, web.ForumResponse{
+			ID:        forum.ID,
+			Title:     forum.Title,
+			Content:   forum.Content,
+			Anonymous: forum.Anonymous,
+			Topics:    topics,
+			CreatedAt: &forum.CreatedAt,
+			UpdatedAt: &forum.UpdatedAt,
+			DeletedAt: deletedAt(forum.DeletedAt),
+		})
+	}
+
+	comments := make([]web.CommentResponse, 0, len(data.Comments))
+	for _, comment := range data.Comments {
+		response := commentResponse(comment)
+		response.Content = comment.Content
+		response.User = nil
+		comments = append(comments, response)
+	}
+
+	reactions := make([]web.ReactionEntry, 0, len(data.Reactions))
+	for _, reaction := range data.Reactions {
+		reactions = append(reactions, web.ReactionEntry{
+			TargetType: reaction.TargetType,
+			TargetID:   reaction.TargetID,
+			Type:       reaction.Type,
+			CreatedAt:  reaction.CreatedAt,
+		})
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", web.UserResponse{
+			ID:        data.User.ID,
+			Name:      data.User.Name,
+			Email:     data.User.Email,
+			Role:      data.User.Role,
+			CreatedAt: &data.User.CreatedAt,
+			UpdatedAt: &data.User.UpdatedAt,
+		}},
+		{"journals.json", journals},
+		{"forums.json", forums},
+		{"comments.json", comments},
+		{"reactions.json", reactions},
+	}
+
+	for _, f := range files {
+		w, err := archive.Create(f.name)
+		if err != nil {
+			return "", err
+		}
+
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(f.data); err != nil {
+			return "", err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return "", err
+	}
+
+	if err := file.Close(); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+func (s *accountService) removeExport(export domain.DataExport) error {
+	if export.File != "" {
+		if err := os.Remove(export.File); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return s.accountRepository.DeleteExport(&export)
+}
+
+// erase removes the archives of the user, then their account following the
+// erasure mode they picked.
+func (s *accountService) erase(deletion domain.AccountDeletion) error {
+	exports, err := s.accountRepository.FindExportsByUser(deletion.UserID)
+	if err != nil {
+		return err
+	}
+
+	for _, export := range exports {
+		if err := s.removeExport(export); err != nil {
+			return err
+		}
+	}
+
+	return s.accountRepository.Erase(deletion.UserID, deletion.Mode)
+}
+
+// findExport returns an export of the user, the exports of other users are
+// not found.
+func (s *accountService) findExport(req web.DataExportFindByID) (*domain.DataExport, error) {
+	export, err := s.accountRepository.FindExport(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.UserID != req.UserID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return export, nil
+}
+
+func dataExportResponse(export domain.DataExport) *web.DataExportResponse {
+	return &web.DataExportResponse{
+		ID:        export.ID,
+		Status:    export.Status,
+		Error:     export.Error,
+		ExpiresAt: export.ExpiresAt,
+		CreatedAt: &export.CreatedAt,
+	}
+}
+
+func accountDeletionResponse(deletion domain.AccountDeletion) *web.AccountDeletionResponse {
+	return &web.AccountDeletionResponse{
+		Mode:         deletion.Mode,
+		ScheduledFor: &deletion.ScheduledFor,
+		CreatedAt:    &deletion.CreatedAt,
+	}
+}
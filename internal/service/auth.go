@@ -0,0 +1,746 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/lockout"
+	"github.com/aternity/zense/internal/mail"
+	"github.com/aternity/zense/internal/oidc"
+	"github.com/aternity/zense/internal/repository"
+	"github.com/aternity/zense/internal/util"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type AuthService interface {
+	Register(req web.UserRegister) (*web.UserResponse, error)
+	Login(req web.UserLogin) (*web.UserAuth, *web.AuthChallenge, error)
+	VerifyTwoFactor(req web.AuthTwoFactorVerify) (*web.UserAuth, error)
+	OIDCProviders() *web.OIDCProviders
+	StartOIDC(ctx context.Context, req web.OIDCStart) (*web.OIDCStartResponse, error)
+	LoginOIDC(ctx context.Context, req web.OIDCCallback) (*web.UserAuth, *web.AuthChallenge, error)
+	Refresh(req web.AuthRefresh) (*web.UserAuth, error)
+	Logout(req web.AuthLogout) error
+	LogoutAll(req web.AuthLogout) error
+	VerifyEmail(req web.AuthVerifyEmail) error
+	ResendVerification(req web.AuthResendVerification) error
+	ForgotPassword(req web.AuthForgotPassword) error
+	ResetPassword(req web.AuthResetPassword) error
+	IsRevoked(tokenID string) (bool, error)
+	Run(ctx context.Context)
+}
+
+type AuthConfig struct {
+	// RefreshTTL is how long a refresh token can be used, every refresh
+	// starts it over.
+	RefreshTTL time.Duration
+	// WorkerInterval is how often expired refresh tokens, revoked access
+	// tokens and mailed tokens are removed.
+	WorkerInterval time.Duration
+	// VerifyTTL and ResetTTL are how long the links mailed to verify an
+	// email and to reset a password work.
+	VerifyTTL time.Duration
+	ResetTTL  time.Duration
+	// ChallengeTTL is how long a login has to verify the second factor.
+	ChallengeTTL time.Duration
+	// ReauthWindow is how recently users without a password or two-factor
+	// authentication must have logged in to change their email or
+	// password, see reauthenticate.
+	ReauthWindow time.Duration
+	// AppURL is where the links in the mails point to, the app reads the
+	// token from the query and posts it back.
+	AppURL string
+}
+
+// mailTimeout bounds sending a mail, mails are sent in the background.
+const mailTimeout = 30 * time.Second
+
+// dummyPassword is compared against when the email of a login is unknown, so
+// unknown emails take as long to refuse as wrong passwords.
+var dummyPassword = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+	return hash
+})
+
+type authService struct {
+	userRepository      repository.UserRepository
+	sessionRepository   repository.SessionRepository
+	userTokenRepository repository.UserTokenRepository
+	twoFactorRepository repository.TwoFactorRepository
+	identityRepository  repository.IdentityRepository
+	providers           oidc.Providers
+	mailer              mail.Mailer
+	lockout             *lockout.Tracker
+	jwt                 *util.JWT
+	config              AuthConfig
+}
+
+func NewAuthService(userRepository repository.UserRepository, sessionRepository repository.SessionRepository, userTokenRepository repository.UserTokenRepository, twoFactorRepository repository.TwoFactorRepository, identityRepository repository.IdentityRepository, providers oidc.Providers, mailer mail.Mailer, lockout *lockout.Tracker, jwt *util.JWT, config AuthConfig) AuthService {
+	return &authService{
+		userRepository:      userRepository,
+		sessionRepository:   sessionRepository,
+		userTokenRepository: userTokenRepository,
+		twoFactorRepository: twoFactorRepository,
+		identityRepository:  identityRepository,
+		providers:           providers,
+		mailer:              mailer,
+		lockout:             lockout,
+		jwt:                 jwt,
+		config:              config,
+	}
+}
+
+// Register creates an unverified user and mails them a link to verify their
+// email.
+func (s *authService) Register(req web.UserRegister) (*web.UserResponse, error) {
+	if reservedEmail(req.Email) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "this email address is reserved")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to hash password")
+	}
+
+	user := &domain.User{
+		Name:     req.Name,
+		Email:    req.Email,
+		Password: string(hashedPassword),
+		Role:     domain.RegularUser,
+	}
+
+	user, err = s.userRepository.Create(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sendVerification(s.userTokenRepository, s.mailer, s.config, user); err != nil {
+		logrus.WithError(err).WithField("user_id", user.ID).Error("failed to send verification email")
+	}
+
+	response := &web.UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		CreatedAt: &user.CreatedAt,
+	}
+
+	return response, nil
+}
+
+// Login checks the credentials of the user and starts a new session. Wrong
+// emails and wrong passwords get the same error, and both count towards
+// locking out the account and the IP address. Users with two-factor
+// authentication get a challenge instead, to exchange for the session with
+// VerifyTwoFactor.
+func (s *authService) Login(req web.UserLogin) (*web.UserAuth, *web.AuthChallenge, error) {
+	if err := s.lockout.Check(req.Email, req.IP); err != nil {
+		return nil, nil, err
+	}
+
+	password := dummyPassword()
+	user, err := s.userRepository.FindByEmail(req.Email)
+	if err == nil {
+		password = []byte(user.Password)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword(password, []byte(req.Password)); err != nil || user == nil {
+		if err := s.lockout.Fail(req.Email, req.IP); err != nil {
+			return nil, nil, err
+		}
+
+		return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, "invalid email or password")
+	}
+
+	// The failures are only forgotten once the second factor is verified too,
+	// or knowing the password would allow guessing codes forever.
+	challenge, err := s.challenge(user)
+	if err != nil || challenge != nil {
+		return nil, challenge, err
+	}
+
+	if err := s.lockout.Succeed(req.Email); err != nil {
+		return nil, nil, err
+	}
+
+	auth, err := s.start(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return auth, nil, nil
+}
+
+// VerifyTwoFactor starts the session of a login challenge with a code of the
+// authenticator or a recovery code. A challenge can be tried once, wrong
+// codes count towards the lockout like wrong passwords.
+func (s *authService) VerifyTwoFactor(req web.AuthTwoFactorVerify) (*web.UserAuth, error) {
+	_, user, err := s.use(req.ChallengeToken, domain.LoginChallengeToken)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.lockout.Check(user.Email, req.IP); err != nil {
+		return nil, err
+	}
+
+	twoFactor, err := s.twoFactorRepository.FindByUser(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	ok := false
+	if twoFactor != nil && twoFactor.EnabledAt != nil {
+		ok, err = verifyTwoFactor(s.twoFactorRepository, twoFactor, req.Code)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !ok {
+		if err := s.lockout.Fail(user.Email, req.IP); err != nil {
+			return nil, err
+		}
+
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "invalid code, log in again")
+	}
+
+	if err := s.lockout.Succeed(user.Email); err != nil {
+		return nil, err
+	}
+
+	return s.start(user)
+}
+
+// Refresh rotates a refresh token, returning a new access token and the
+// refresh token to use next time. Using a rotated refresh token again means
+// it leaked, so the whole session is revoked.
+func (s *authService) Refresh(req web.AuthRefresh) (*web.UserAuth, error) {
+	current, err := s.sessionRepository.FindByHash(util.HashToken(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, echo.NewHTTPError(http.StatusUnauthorized, "invalid refresh token")
+		}
+		return nil, err
+	}
+
+	if current.RevokedAt != nil {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "refresh token was revoked")
+	}
+
+	if current.RotatedAt != nil {
+		return nil, s.reused(current)
+	}
+
+	if current.ExpiresAt.Before(time.Now()) {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "refresh token has expired")
+	}
+
+	user, err := s.userRepository.FindByID(current.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, echo.NewHTTPError(http.StatusUnauthorized, "invalid refresh token")
+		}
+		return nil, err
+	}
+
+	next, auth, err := s.issue(user, current.SessionID, current.AuthenticatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.sessionRepository.Rotate(current, next); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, s.reused(current)
+		}
+		return nil, err
+	}
+
+	return auth, nil
+}
+
+// Logout revokes the access token of the request and its session.
+func (s *authService) Logout(req web.AuthLogout) error {
+	if err := s.deny(req); err != nil {
+		return err
+	}
+
+	if req.SessionID == "" {
+		return nil
+	}
+
+	return s.sessionRepository.RevokeSession(req.SessionID)
+}
+
+// LogoutAll revokes every session of the user, on every device.
+func (s *authService) LogoutAll(req web.AuthLogout) error {
+	if err := s.deny(req); err != nil {
+		return err
+	}
+
+	return s.sessionRepository.RevokeUser(req.UserID)
+}
+
+// VerifyEmail marks the email of the user as verified. The token only works
+// while the user still has the email it was sent to.
+func (s *authService) VerifyEmail(req web.AuthVerifyEmail) error {
+	token, user, err := s.use(req.Token, domain.VerifyEmailToken)
+	if err != nil {
+		return err
+	}
+
+	if user.VerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	return s.userRepository.SetVerified(token.UserID, &now)
+}
+
+func (s *authService) ResendVerification(req web.AuthResendVerification) error {
+	user, err := s.userRepository.FindByID(req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user.VerifiedAt != nil {
+		return echo.NewHTTPError(http.StatusConflict, "email already verified")
+	}
+
+	return sendVerification(s.userTokenRepository, s.mailer, s.config, user)
+}
+
+// ForgotPassword mails a link to reset the password. It succeeds for unknown
+// emails too, so it cannot tell which emails are registered.
+func (s *authService) ForgotPassword(req web.AuthForgotPassword) error {
+	user, err := s.userRepository.FindByEmail(req.Email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := createToken(s.userTokenRepository, user, domain.ResetPasswordToken, s.config.ResetTTL)
+	if err != nil {
+		return err
+	}
+	link := s.config.AppURL + "/reset-password?token=" + token
+
+	send(s.mailer, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your Zense password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your Zense account. Open this link within %s to choose a new one:\n\n%s\n\nIf it was not you, you can ignore this email, your password stays the same.\n",
+			user.Name, s.config.ResetTTL, link),
+	})
+
+	return nil
+}
+
+// ResetPassword sets a new password and revokes every session of the user.
+// The mail proved the user owns the email, so it is verified too, and the
+// failed logins of the account are forgotten.
+func (s *authService) ResetPassword(req web.AuthResetPassword) error {
+	token, user, err := s.use(req.Token, domain.ResetPasswordToken)
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "failed to hash password")
+	}
+
+	if _, err := s.userRepository.Update(&domain.User{
+		ID:       token.UserID,
+		Password: string(hashedPassword),
+	}); err != nil {
+		return err
+	}
+
+	if user.VerifiedAt == nil {
+		now := time.Now()
+		if err := s.userRepository.SetVerified(token.UserID, &now); err != nil {
+			return err
+		}
+	}
+
+	if err := s.lockout.Succeed(user.Email); err != nil {
+		return err
+	}
+
+	return s.sessionRepository.RevokeUser(token.UserID)
+}
+
+func (s *authService) OIDCProviders() *web.OIDCProviders {
+	return &web.OIDCProviders{Providers: oidcProviders(s.providers)}
+}
+
+// StartOIDC starts a sign in at the provider.
+func (s *authService) StartOIDC(ctx context.Context, req web.OIDCStart) (*web.OIDCStartResponse, error) {
+	return startOIDC(ctx, s.identityRepository, s.providers, req.Provider, nil)
+}
+
+// LoginOIDC signs in the user of the identity the provider returned. An
+// identity seen for the first time is linked to the account with its email,
+// or a new account is created for it, but only when the provider verified
+// the email. Users with two-factor authentication get a challenge like with
+// Login.
+func (s *authService) LoginOIDC(ctx context.Context, req web.OIDCCallback) (*web.UserAuth, *web.AuthChallenge, error) {
+	state, identity, err := finishOIDC(ctx, s.identityRepository, s.providers, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if state.UserID != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "invalid or expired state")
+	}
+
+	user, err := s.oidcUser(req.Provider, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	challenge, err := s.challenge(user)
+	if err != nil || challenge != nil {
+		return nil, challenge, err
+	}
+
+	auth, err := s.start(user)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return auth, nil, nil
+}
+
+func (s *authService) IsRevoked(tokenID string) (bool, error) {
+	return s.sessionRepository.IsDenied(tokenID)
+}
+
+// Run removes the expired refresh tokens, revoked access tokens, mailed tokens,
+// login attempts and OIDC states until ctx is done.
+func (s *authService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.WorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.sessionRepository.DeleteExpired(time.Now()); err != nil {
+			logrus.WithError(err).Error("failed to remove expired sessions")
+		}
+
+		if err := s.userTokenRepository.DeleteExpired(time.Now()); err != nil {
+			logrus.WithError(err).Error("failed to remove expired user tokens")
+		}
+
+		if err := s.lockout.Cleanup(); err != nil {
+			logrus.WithError(err).Error("failed to remove expired login attempts")
+		}
+
+		if err := s.identityRepository.DeleteExpiredStates(time.Now()); err != nil {
+			logrus.WithError(err).Error("failed to remove expired oidc states")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// challenge returns a login challenge when the user has two-factor
+// authentication, nil when they do not.
+func (s *authService) challenge(user *domain.User) (*web.AuthChallenge, error) {
+	twoFactor, err := s.twoFactorRepository.FindByUser(user.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if twoFactor.EnabledAt == nil {
+		return nil, nil
+	}
+
+	token, err := createToken(s.userTokenRepository, user, domain.LoginChallengeToken, s.config.ChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &web.AuthChallenge{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+		ExpiresAt:         time.Now().Add(s.config.ChallengeTTL),
+	}, nil
+}
+
+// oidcUser returns the user of an identity, linking or creating it the first
+// time. Accounts whose email is not verified are not linked, whoever
+// registered them may not own the email.
+func (s *authService) oidcUser(provider string, identity *oidc.Identity) (*domain.User, error) {
+	linked, err := s.identityRepository.FindBySubject(provider, identity.Subject)
+	if err == nil {
+		return s.userRepository.FindByID(linked.UserID)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "the provider did not verify your email")
+	}
+
+	if reservedEmail(identity.Email) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "this email address is reserved")
+	}
+
+	user, err := s.userRepository.FindByEmail(identity.Email)
+	switch {
+	case err == nil:
+		if user.VerifiedAt == nil {
+			return nil, echo.NewHTTPError(http.StatusConflict, "an account with this email is not verified yet, verify it or log in and connect the provider")
+		}
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// The account has no password until the user sets one.
+		now := time.Now()
+		user, err = s.userRepository.Create(&domain.User{
+			Name:       oidcName(identity),
+			Email:      identity.Email,
+			Role:       domain.RegularUser,
+			VerifiedAt: &now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if _, err := s.identityRepository.Create(&domain.Identity{
+		UserID:   user.ID,
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// oidcName fits the name of an identity to the user names, falling back to
+// the start of the email.
+func oidcName(identity *oidc.Identity) string {
+	name := strings.TrimSpace(identity.Name)
+	if name == "" {
+		name, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	if runes := []rune(name); len(runes) > 16 {
+		name = string(runes[:16])
+	}
+	return name
+}
+
+// start starts a new session of the user.
+func (s *authService) start(user *domain.User) (*web.UserAuth, error) {
+	sessionID, err := util.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	token, auth, err := s.issue(user, sessionID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.sessionRepository.Create(token); err != nil {
+		return nil, err
+	}
+
+	return auth, nil
+}
+
+// issue creates an access token and a refresh token of the session the user
+// logged in to at authenticatedAt. The refresh token is returned to be
+// stored, only its hash is kept.
+func (s *authService) issue(user *domain.User, sessionID string, authenticatedAt time.Time) (*domain.RefreshToken, *web.UserAuth, error) {
+	access, claims, err := s.jwt.GenerateToken(user.ID, string(user.Role), user.VerifiedAt != nil, sessionID, authenticatedAt)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to generate token")
+	}
+
+	refresh, err := util.RandomToken(32)
+	if err != nil {
+		return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to generate token")
+	}
+
+	token := &domain.RefreshToken{
+		UserID:          user.ID,
+		SessionID:       sessionID,
+		Hash:            util.HashToken(refresh),
+		AccessTokenID:   claims.ID,
+		AccessExpiresAt: claims.ExpiresAt.Time,
+		ExpiresAt:       time.Now().Add(s.config.RefreshTTL),
+		AuthenticatedAt: authenticatedAt,
+	}
+
+	auth := &web.UserAuth{
+		ID:           user.ID,
+		Name:         user.Name,
+		Role:         user.Role,
+		Verified:     user.VerifiedAt != nil,
+		Token:        access,
+		ExpiresAt:    &claims.ExpiresAt.Time,
+		RefreshToken: refresh,
+	}
+
+	return token, auth, nil
+}
+
+// reused revokes the session of a refresh token that was used twice.
+func (s *authService) reused(token *domain.RefreshToken) error {
+	if err := s.sessionRepository.RevokeSession(token.SessionID); err != nil {
+		return err
+	}
+
+	logrus.WithField("user_id", token.UserID).Warn("refresh token reused, session revoked")
+	return echo.NewHTTPError(http.StatusUnauthorized, "refresh token was already used, the session has been revoked")
+}
+
+func (s *authService) deny(req web.AuthLogout) error {
+	if req.TokenID == "" {
+		return nil
+	}
+
+	return s.sessionRepository.Deny(&domain.RevokedToken{
+		ID:        req.TokenID,
+		ExpiresAt: req.ExpiresAt,
+	})
+}
+
+// reauthenticate confirms a sensitive request is made by the user: with the
+// password when the account has one and with a two-factor code when it is
+// enabled. Accounts with neither, created through a provider, must have
+// logged in within window of the request.
+func reauthenticate(twoFactorRepository repository.TwoFactorRepository, user *domain.User, password string, code string, authenticatedAt time.Time, window time.Duration) error {
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid password")
+		}
+	}
+
+	twoFactor, err := twoFactorRepository.FindByUser(user.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if twoFactor != nil && twoFactor.EnabledAt != nil {
+		ok, err := verifyTwoFactor(twoFactorRepository, twoFactor, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return echo.NewHTTPError(http.StatusUnauthorized, "invalid code")
+		}
+		return nil
+	}
+
+	if user.Password == "" && time.Since(authenticatedAt) > window {
+		return echo.NewHTTPError(http.StatusUnauthorized, "log in again to confirm")
+	}
+
+	return nil
+}
+
+// use consumes a mailed token and returns it with its user. The token is
+// refused when the user changed their email since it was sent.
+func (s *authService) use(value string, purpose domain.UserTokenPurpose) (*domain.UserToken, *domain.User, error) {
+	invalid := echo.NewHTTPError(http.StatusBadRequest, "invalid or expired token")
+
+	token, err := s.userTokenRepository.Use(util.HashToken(value), purpose)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, invalid
+		}
+		return nil, nil, err
+	}
+
+	user, err := s.userRepository.FindByID(token.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, invalid
+		}
+		return nil, nil, err
+	}
+
+	if user.Email != token.Email {
+		return nil, nil, invalid
+	}
+
+	return token, user, nil
+}
+
+// createToken stores a new token of the user for purpose and returns it.
+func createToken(userTokenRepository repository.UserTokenRepository, user *domain.User, purpose domain.UserTokenPurpose, ttl time.Duration) (string, error) {
+	value, err := util.RandomToken(32)
+	if err != nil {
+		return "", echo.NewHTTPError(http.StatusInternalServerError, "failed to generate token")
+	}
+
+	if _, err := userTokenRepository.Create(&domain.UserToken{
+		UserID:    user.ID,
+		Purpose:   purpose,
+		Email:     user.Email,
+		Hash:      util.HashToken(value),
+		ExpiresAt: time.Now().Add(ttl),
+	}); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// sendVerification mails the user a link to verify their email.
+func sendVerification(userTokenRepository repository.UserTokenRepository, mailer mail.Mailer, config AuthConfig, user *domain.User) error {
+	token, err := createToken(userTokenRepository, user, domain.VerifyEmailToken, config.VerifyTTL)
+	if err != nil {
+		return err
+	}
+	link := config.AppURL + "/verify-email?token=" + token
+
+	send(mailer, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your Zense email",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen this link within %s to verify your email and start posting on Zense:\n\n%s\n\nIf you did not sign up, you can ignore this email.\n",
+			user.Name, config.VerifyTTL, link),
+	})
+
+	return nil
+}
+
+// send mails message in the background, so neither slow mail servers nor
+// whether a mail was sent show in the response.
+func send(mailer mail.Mailer, message mail.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+		defer cancel()
+
+		if err := mailer.Send(ctx, message); err != nil {
+			logrus.WithError(err).WithField("subject", message.Subject).Error("failed to send email")
+		}
+	}()
+}
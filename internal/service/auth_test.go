@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/lockout"
+	"github.com/aternity/zense/internal/oidc"
+	"github.com/aternity/zense/internal/repository"
+	"github.com/aternity/zense/internal/util"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testPassword = "correct horse battery staple"
+
+type testAuth struct {
+	service  AuthService
+	users    *fakeUsers
+	sessions *fakeSessions
+	jwt      *util.JWT
+}
+
+// newTestAuth returns an auth service on fakes whose only user is
+// user@example.com with testPassword.
+func newTestAuth(t *testing.T, providers oidc.Providers, identities repository.IdentityRepository) *testAuth {
+	t.Helper()
+	password, err := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword() = %v", err)
+	}
+	verifiedAt := time.Now()
+
+	jwt, err := util.NewJWT("zense", "zense", 15*time.Minute, util.NewHMACKey("test secret"))
+	if err != nil {
+		t.Fatalf("NewJWT() = %v", err)
+	}
+
+	test := &testAuth{
+		users:    newFakeUsers(domain.User{ID: 1, Name: "User", Email: "user@example.com", Password: string(password), Role: domain.RegularUser, VerifiedAt: &verifiedAt}),
+		sessions: newFakeSessions(),
+		jwt:      jwt,
+	}
+	tracker := lockout.NewTracker(lockout.NewMemory(), lockout.Policy{AccountThreshold: 5, IPThreshold: 20, BaseDelay: time.Minute, MaxDelay: time.Hour, Window: time.Hour})
+	test.service = NewAuthService(test.users, test.sessions, nil, newFakeTwoFactors(), identities, providers, nil, tracker, jwt, AuthConfig{RefreshTTL: time.Hour})
+	return test
+}
+
+func (a *testAuth) login(t *testing.T) *web.UserAuth {
+	t.Helper()
+	auth, challenge, err := a.service.Login(web.UserLogin{Email: "user@example.com", Password: testPassword})
+	if err != nil || challenge != nil {
+		t.Fatalf("Login() = %v, %v", challenge, err)
+	}
+	return auth
+}
+
+func (a *testAuth) claims(t *testing.T, auth *web.UserAuth) *util.Claims {
+	t.Helper()
+	claims, err := a.jwt.ValidateToken(auth.Token)
+	if err != nil {
+		t.Fatalf("ValidateToken() = %v", err)
+	}
+	return claims
+}
+
+func (a *testAuth) revoked(t *testing.T, auth *web.UserAuth) bool {
+	t.Helper()
+	revoked, err := a.service.IsRevoked(a.claims(t, auth).ID)
+	if err != nil {
+		t.Fatalf("IsRevoked() = %v", err)
+	}
+	return revoked
+}
+
+func wantStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var httpErr *echo.HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Code != status {
+		t.Fatalf("error = %v, want status %d", err, status)
+	}
+}
+
+func TestRefreshRotatesToken(t *testing.T) {
+	a := newTestAuth(t, nil, nil)
+	first := a.login(t)
+
+	second, err := a.service.Refresh(web.AuthRefresh{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("Refresh() = %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken || second.Token == first.Token {
+		t.Fatal("Refresh() returned the tokens it was given")
+	}
+
+	before, after := a.claims(t, first), a.claims(t, second)
+	if after.SessionID != before.SessionID {
+		t.Errorf("session %s after the refresh, want %s", after.SessionID, before.SessionID)
+	}
+	if !after.AuthTime.Equal(before.AuthTime.Time) {
+		t.Errorf("auth_time %s after the refresh, want %s", after.AuthTime, before.AuthTime)
+	}
+
+	if _, err := a.service.Refresh(web.AuthRefresh{RefreshToken: second.RefreshToken}); err != nil {
+		t.Fatalf("Refresh() with the rotated token = %v", err)
+	}
+}
+
+func TestRefreshReuseRevokesSession(t *testing.T) {
+	a := newTestAuth(t, nil, nil)
+	first := a.login(t)
+	other := a.login(t)
+
+	second, err := a.service.Refresh(web.AuthRefresh{RefreshToken: first.RefreshToken})
+	if err != nil {
+		t.Fatalf("Refresh() = %v", err)
+	}
+
+	_, err = a.service.Refresh(web.AuthRefresh{RefreshToken: first.RefreshToken})
+	wantStatus(t, err, http.StatusUnauthorized)
+
+	_, err = a.service.Refresh(web.AuthRefresh{RefreshToken: second.RefreshToken})
+	wantStatus(t, err, http.StatusUnauthorized)
+
+	if !a.revoked(t, first) || !a.revoked(t, second) {
+		t.Error("the access tokens of the reused session were not revoked")
+	}
+
+	if a.revoked(t, other) {
+		t.Error("the access token of another session was revoked")
+	}
+	if _, err := a.service.Refresh(web.AuthRefresh{RefreshToken: other.RefreshToken}); err != nil {
+		t.Errorf("Refresh() of another session = %v", err)
+	}
+}
@@ -1,38 +1,104 @@
 package service
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/aternity/zense/internal/entity/domain"
 	"github.com/aternity/zense/internal/entity/web"
 	"github.com/aternity/zense/internal/repository"
+	"github.com/aternity/zense/internal/util"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type CommentService interface {
 	Create(req web.CommentCreate) (*web.CommentResponse, error)
-	FindAll() ([]web.CommentResponse, error)
+	FindAll(req web.CommentFindAll) (*web.PageResponse[web.CommentResponse], error)
+	FindByForum(req web.CommentFindByForum) (*web.PageResponse[web.CommentResponse], error)
 	FindByID(req web.CommentFindByID) (*web.CommentResponse, error)
 	Update(req web.CommentUpdate) (*web.CommentResponse, error)
 	Delete(req web.CommentDelete) error
+	Restore(req web.CommentRestore) (*web.CommentResponse, error)
+}
+
+// defaultReplyLimit is the number of replies embedded under each comment of
+// a thread when the request does not ask for another one.
+const defaultReplyLimit = 3
+
+type CommentConfig struct {
+	MaxDepth int
 }
 
 type commentService struct {
-	repository repository.CommentRepository
+	repository         repository.CommentRepository
+	forumRepository    repository.ForumRepository
+	reactionRepository repository.ReactionRepository
+	pseudonyms         *util.Pseudonyms
+	maxDepth           int
 }
 
-func NewCommentService(repository repository.CommentRepository) CommentService {
+func NewCommentService(
+	repository repository.CommentRepository,
+	forumRepository repository.ForumRepository,
+	reactionRepository repository.ReactionRepository,
+	pseudonyms *util.Pseudonyms,
+	config CommentConfig,
+) CommentService {
 	return &commentService{
-		repository: repository,
+		repository:         repository,
+		forumRepository:    forumRepository,
+		reactionRepository: reactionRepository,
+		pseudonyms:         pseudonyms,
+		maxDepth:           config.MaxDepth,
 	}
 }
 
 func (s *commentService) Create(req web.CommentCreate) (*web.CommentResponse, error) {
+	if _, err := s.forumRepository.FindByID(req.ForumID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, echo.NewHTTPError(http.StatusNotFound, "forum not found")
+		}
+		return nil, err
+	}
+
+	depth := 0
+	if req.ParentID != nil {
+		parent, err := s.repository.FindByID(*req.ParentID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, echo.NewHTTPError(http.StatusNotFound, "parent comment not found")
+			}
+			return nil, err
+		}
+
+		if parent.Visibility != domain.PublicComment && parent.UserID != req.UserID {
+			return nil, echo.NewHTTPError(http.StatusNotFound, "parent comment not found")
+		}
+
+		if parent.ForumID != req.ForumID {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "parent comment belongs to another forum")
+		}
+
+		if parent.Deleted {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "cannot reply to a deleted comment")
+		}
+
+		if parent.Depth >= s.maxDepth {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "maximum reply depth reached")
+		}
+
+		depth = parent.Depth + 1
+	}
+
 	comment := &domain.Comment{
 		UserID:     req.UserID,
 		ForumID:    req.ForumID,
+		ParentID:   req.ParentID,
+		Depth:      depth,
 		Content:    req.Content,
-		Visibility: req.Visibility,
+		Visibility: reviewed(req.Visibility),
+		Anonymous:  req.Anonymous,
 	}
 
 	comment, err := s.repository.Create(comment)
@@ -44,37 +110,143 @@ func (s *commentService) Create(req web.CommentCreate) (*web.CommentResponse, er
 		ID:         comment.ID,
 		UserID:     comment.UserID,
 		ForumID:    comment.ForumID,
+		ParentID:   comment.ParentID,
+		Depth:      comment.Depth,
 		Content:    comment.Content,
 		Visibility: comment.Visibility,
 		CreatedAt:  &comment.CreatedAt,
 	}
+	if comment.Anonymous {
+		response.Anonymous = true
+		response.Pseudonym = s.pseudonyms.Name(comment.ForumID, comment.UserID)
+	}
 
 	return response, nil
 }
 
-func (s *commentService) FindAll() ([]web.CommentResponse, error) {
-	comments, err := s.repository.FindAll()
+func (s *commentService) FindAll(req web.CommentFindAll) (*web.PageResponse[web.CommentResponse], error) {
+	filter := repository.CommentFilter{
+		ForumID:          req.ForumID,
+		UserID:           req.AuthorID,
+		ExcludeAnonymous: req.AuthorID != 0 && req.AuthorID != req.UserID && !isModerator(req.Role),
+	}
+
+	comments, err := s.repository.FindAll(req.UserID, filter, pagination(req.PageQuery, req.Sort))
 	if err != nil {
+		return nil, pageError(err)
+	}
+
+	response := pageResponse(comments, req.PageQuery, s.commentResponse(req.UserID, req.Role))
+	if err := s.withReactions(response.Data, req.UserID); err != nil {
 		return nil, err
 	}
 
-	var responses []web.CommentResponse
-	for _, comment := range comments {
-		responses = append(responses, web.CommentResponse{
-			ID:         comment.ID,
-			ForumID:    comment.ForumID,
-			Content:    comment.Content,
-			Visibility: comment.Visibility,
-			CreatedAt:  &comment.CreatedAt,
-			UpdatedAt:  &comment.UpdatedAt,
-			User: &web.UserResponse{
-				ID:   comment.User.ID,
-				Name: comment.User.Name,
-			},
-		})
+	return response, nil
+}
+
+// FindByForum returns the thread of a forum. The tree mode pages through one
+// level and embeds the first replies of every comment below it, the flat mode
+// pages through the whole thread in chronological order.
+func (s *commentService) FindByForum(req web.CommentFindByForum) (*web.PageResponse[web.CommentResponse], error) {
+	if _, err := s.forumRepository.FindByID(req.ForumID); err != nil {
+		return nil, err
+	}
+
+	// Threads are read oldest first unless asked otherwise.
+	if req.Order == "" {
+		req.Order = "asc"
+	}
+
+	if req.Mode == "flat" {
+		filter := repository.CommentFilter{
+			ForumID: req.ForumID,
+		}
+
+		comments, err := s.repository.FindAll(req.UserID, filter, pagination(req.PageQuery, "created_at"))
+		if err != nil {
+			return nil, pageError(err)
+		}
+
+		response := pageResponse(comments, req.PageQuery, s.commentResponse(req.UserID, req.Role))
+		if err := s.withReactions(response.Data, req.UserID); err != nil {
+			return nil, err
+		}
+
+		return response, nil
 	}
 
-	return responses, nil
+	var parentID *uint
+	if req.ParentID != 0 {
+		parentID = &req.ParentID
+	}
+
+	comments, err := s.repository.FindByForum(req.ForumID, parentID, req.UserID, pagination(req.PageQuery, "created_at"))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	replyLimit := req.ReplyLimit
+	if replyLimit <= 0 {
+		replyLimit = defaultReplyLimit
+	}
+
+	// Load the replies level by level, one query per level.
+	replies := make(map[uint]*repository.Page[domain.Comment])
+	level := comments.Items
+	var thread []uint
+	for depth := 0; len(level) > 0 && depth < s.maxDepth; depth++ {
+		ids := make([]uint, 0, len(level))
+		for _, comment := range level {
+			ids = append(ids, comment.ID)
+		}
+		thread = append(thread, ids...)
+
+		pages, err := s.repository.FindReplies(ids, req.UserID, replyLimit)
+		if err != nil {
+			return nil, err
+		}
+
+		level = nil
+		for id, page := range pages {
+			replies[id] = page
+			level = append(level, page.Items...)
+		}
+	}
+
+	for _, comment := range level {
+		thread = append(thread, comment.ID)
+	}
+
+	reactions, err := reactionSummaries(s.reactionRepository, domain.CommentTarget, thread, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	commentResponse := s.commentResponse(req.UserID, req.Role)
+
+	var build func(comment domain.Comment) web.CommentResponse
+	build = func(comment domain.Comment) web.CommentResponse {
+		response := commentResponse(comment)
+		response.Reactions = reactions[comment.ID]
+
+		page, ok := replies[comment.ID]
+		if !ok {
+			return response
+		}
+
+		response.Replies = &web.CommentReplies{
+			Data:       make([]web.CommentResponse, 0, len(page.Items)),
+			NextCursor: page.NextCursor,
+			Total:      page.Total,
+		}
+		for _, reply := range page.Items {
+			response.Replies.Data = append(response.Replies.Data, build(reply))
+		}
+
+		return response
+	}
+
+	return pageResponse(comments, req.PageQuery, build), nil
 }
 
 func (s *commentService) FindByID(req web.CommentFindByID) (*web.CommentResponse, error) {
@@ -83,20 +255,16 @@ func (s *commentService) FindByID(req web.CommentFindByID) (*web.CommentResponse
 		return nil, err
 	}
 
-	response := &web.CommentResponse{
-		ID:         comment.ID,
-		ForumID:    comment.ForumID,
-		Content:    comment.Content,
-		Visibility: comment.Visibility,
-		CreatedAt:  &comment.CreatedAt,
-		UpdatedAt:  &comment.UpdatedAt,
-		User: &web.UserResponse{
-			ID:   comment.User.ID,
-			Name: comment.User.Name,
-		},
+	if comment.Visibility != domain.PublicComment && comment.UserID != req.UserID && !isModerator(req.Role) {
+		return nil, gorm.ErrRecordNotFound
 	}
 
-	return response, nil
+	responses := []web.CommentResponse{s.commentResponse(req.UserID, req.Role)(*comment)}
+	if err := s.withReactions(responses, req.UserID); err != nil {
+		return nil, err
+	}
+
+	return &responses[0], nil
 }
 
 func (s *commentService) Update(req web.CommentUpdate) (*web.CommentResponse, error) {
@@ -106,13 +274,23 @@ func (s *commentService) Update(req web.CommentUpdate) (*web.CommentResponse, er
 	}
 
 	if comment.UserID != req.UserID {
-		return nil, echo.NewHTTPError(http.StatusForbidden, "user does not have permission to delete this comment")
+		return nil, echo.NewHTTPError(http.StatusForbidden, "user does not have permission to update this comment")
+	}
+
+	if comment.Deleted {
+		return nil, echo.NewHTTPError(http.StatusConflict, "comment was deleted")
+	}
+
+	// Edited public comments go through review again.
+	visibility := reviewed(req.Visibility)
+	if visibility == "" && req.Content != "" && comment.Visibility != domain.PrivateComment {
+		visibility = domain.ReviewComment
 	}
 
 	comment = &domain.Comment{
 		ID:         req.ID,
 		Content:    req.Content,
-		Visibility: req.Visibility,
+		Visibility: visibility,
 	}
 
 	comment, err = s.repository.Update(comment)
@@ -141,14 +319,132 @@ func (s *commentService) Delete(req web.CommentDelete) error {
 		return echo.NewHTTPError(http.StatusForbidden, "user does not have permission to delete this comment")
 	}
 
-	comment, err = s.repository.FindByID(req.ID)
+	replies, err := s.repository.CountReplies(comment.ID)
 	if err != nil {
 		return err
 	}
 
-	if err := s.repository.Delete(comment); err != nil {
+	// A comment with replies leaves a placeholder behind so the thread
+	// below it stays readable. Its content is kept until the retention
+	// period is over, so it can be restored.
+	if replies > 0 {
+		_, err := s.repository.Update(&domain.Comment{
+			ID:      comment.ID,
+			Deleted: true,
+		})
+		return err
+	}
+
+	return s.repository.Delete(comment)
+}
+
+// Restore brings back a deleted comment or placeholder. Authors restore their
+// own comments, moderators restore any comment and the restore is logged.
+func (s *commentService) Restore(req web.CommentRestore) (*web.CommentResponse, error) {
+	comment, err := s.repository.FindDeleted(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.UserID != req.UserID && !isModerator(req.Role) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "user does not have permission to restore this comment")
+	}
+
+	if _, err := s.forumRepository.FindByID(comment.ForumID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "forum was deleted, restore the forum instead")
+		}
+		return nil, err
+	}
+
+	if comment.ParentID != nil {
+		if _, err := s.repository.FindByID(*comment.ParentID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, echo.NewHTTPError(http.StatusConflict, "parent comment was deleted, restore it first")
+			}
+			return nil, err
+		}
+	}
+
+	if err := s.repository.Restore(comment, restoreLog(req.UserID, comment.UserID, "comment", comment.ID)); err != nil {
+		return nil, err
+	}
+
+	response := s.commentResponse(req.UserID, req.Role)(*comment)
+	return &response, nil
+}
+
+func (s *commentService) withReactions(responses []web.CommentResponse, userID uint) error {
+	ids := make([]uint, 0, len(responses))
+	for _, comment := range responses {
+		ids = append(ids, comment.ID)
+	}
+
+	reactions, err := reactionSummaries(s.reactionRepository, domain.CommentTarget, ids, userID)
+	if err != nil {
 		return err
 	}
 
+	for i := range responses {
+		responses[i].Reactions = reactions[responses[i].ID]
+	}
+
 	return nil
 }
+
+// reviewed turns a request to publish a comment into a request for review,
+// only moderators can make a comment public.
+func reviewed(visibility domain.CommentVisibility) domain.CommentVisibility {
+	if visibility == domain.PublicComment {
+		return domain.ReviewComment
+	}
+	return visibility
+}
+
+// commentResponse builds the responses seen by userID: anonymous comments are
+// named by the pseudonym of their author in the thread, and who the author is
+// stays hidden from everyone but themselves and moderators.
+func (s *commentService) commentResponse(userID uint, role domain.UserRole) func(domain.Comment) web.CommentResponse {
+	return func(comment domain.Comment) web.CommentResponse {
+		response := commentResponse(comment)
+		if !comment.Anonymous || comment.Deleted {
+			return response
+		}
+
+		response.Pseudonym = s.pseudonyms.Name(comment.ForumID, comment.UserID)
+
+		if comment.UserID != userID && !isModerator(role) {
+			response.UserID = 0
+			response.User = nil
+		}
+
+		return response
+	}
+}
+
+func commentResponse(comment domain.Comment) web.CommentResponse {
+	response := web.CommentResponse{
+		ID:         comment.ID,
+		ForumID:    comment.ForumID,
+		ParentID:   comment.ParentID,
+		Depth:      comment.Depth,
+		Content:    comment.Content,
+		Visibility: comment.Visibility,
+		Deleted:    comment.Deleted,
+		Anonymous:  comment.Anonymous,
+		CreatedAt:  &comment.CreatedAt,
+		UpdatedAt:  &comment.UpdatedAt,
+		DeletedAt:  deletedAt(comment.DeletedAt),
+	}
+
+	if comment.Deleted {
+		response.Content = domain.DeletedCommentContent
+	} else {
+		response.User = &web.UserResponse{
+			ID:   comment.User.ID,
+			Name: comment.User.Name,
+		}
+	}
+
+	return response
+}
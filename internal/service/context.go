@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aternity/zense/internal/llm"
+	"github.com/sirupsen/logrus"
+)
+
+const summaryPrompt = `Summarize the conversation below between a user and a supportive AI companion.
+Keep the user's feelings, important events, names and anything they asked to remember.
+Write it in the same language as the conversation, in at most %d words, as plain text.
+%s
+Conversation:
+%s`
+
+// ventContext keeps the prompt of a session within a token budget. The most
+// recent messages are sent verbatim and older ones are folded into a running
+// summary written by the provider.
+type ventContext struct {
+	llm    llm.Provider
+	budget int
+}
+
+func newVentContext(llm llm.Provider, budget int) *ventContext {
+	return &ventContext{
+		llm:    llm,
+		budget: budget,
+	}
+}
+
+// build renders the prompt for message, compacting the session first when the
+// full history would not fit in the budget.
+func (c *ventContext) build(ctx context.Context, session *ventSession, message string, render func(summary, history string) (string, error)) (string, error) {
+	summary, history := session.snapshot()
+	turns := append(history, message)
+
+	input, err := render(summary, strings.Join(turns, "\n"))
+	if err != nil || c.budget <= 0 || llm.EstimateTokens(input) <= c.budget {
+		return input, err
+	}
+
+	overhead, err := render("", "")
+	if err != nil {
+		return "", err
+	}
+
+	// A quarter of the budget is reserved for the summary itself.
+	available := c.budget - llm.EstimateTokens(overhead) - c.budget/4
+
+	// The latest message is always kept, even if it alone exceeds the budget.
+	cut := len(history)
+	used := llm.EstimateTokens(message)
+	for cut > 0 {
+		tokens := llm.EstimateTokens(history[cut-1])
+		if used+tokens > available {
+			break
+		}
+		used += tokens
+		cut--
+	}
+
+	if cut > 0 {
+		summary = c.summarize(ctx, summary, history[:cut])
+		session.compact(cut, summary)
+	}
+
+	return render(summary, strings.Join(turns[cut:], "\n"))
+}
+
+func (c *ventContext) summarize(ctx context.Context, summary string, messages []string) string {
+	var previous string
+	if summary != "" {
+		previous = fmt.Sprintf("Summary of the conversation before this:\n%s\n", summary)
+	}
+
+	// Roughly 0.75 words per token.
+	words := c.budget / 4 * 3 / 4
+
+	result, err := c.llm.Generate(ctx, fmt.Sprintf(summaryPrompt, words, previous, strings.Join(messages, "\n")))
+	if err != nil {
+		// Dropping the old messages still keeps the prompt within budget.
+		logrus.WithError(err).Warn("failed to summarize vent session")
+		return summary
+	}
+
+	return strings.TrimSpace(result)
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/repository"
+	"gorm.io/gorm"
+)
+
+// The fakes keep the rows of a repository in memory and follow the contracts
+// documented on its methods. The repository interface is embedded so a test
+// calling a method the fake leaves out panics instead of passing silently.
+
+type fakeUsers struct {
+	repository.UserRepository
+
+	mu    sync.Mutex
+	users map[uint]domain.User
+}
+
+func newFakeUsers(users ...domain.User) *fakeUsers {
+	fake := &fakeUsers{users: make(map[uint]domain.User)}
+	for _, user := range users {
+		fake.users[user.ID] = user
+	}
+	return fake
+}
+
+func (f *fakeUsers) FindByID(id uint) (*domain.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	user, ok := f.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &user, nil
+}
+
+func (f *fakeUsers) FindByEmail(email string) (*domain.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, user := range f.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeUsers) Create(user *domain.User) (*domain.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	user.ID = uint(len(f.users) + 1)
+	f.users[user.ID] = *user
+	return user, nil
+}
+
+type fakeSessions struct {
+	repository.SessionRepository
+
+	mu     sync.Mutex
+	tokens []domain.RefreshToken
+	denied map[string]bool
+}
+
+func newFakeSessions() *fakeSessions {
+	return &fakeSessions{denied: make(map[string]bool)}
+}
+
+func (f *fakeSessions) Create(token *domain.RefreshToken) (*domain.RefreshToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	token.ID = uint(len(f.tokens) + 1)
+	f.tokens = append(f.tokens, *token)
+	return token, nil
+}
+
+func (f *fakeSessions) FindByHash(hash string) (*domain.RefreshToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, token := range f.tokens {
+		if token.Hash == hash {
+			return &token, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeSessions) Rotate(token *domain.RefreshToken, next *domain.RefreshToken) (*domain.RefreshToken, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	current := &f.tokens[token.ID-1]
+	if current.RotatedAt != nil || current.RevokedAt != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	now := time.Now()
+	current.RotatedAt = &now
+
+	next.ID = uint(len(f.tokens) + 1)
+	f.tokens = append(f.tokens, *next)
+	return next, nil
+}
+
+func (f *fakeSessions) RevokeSession(sessionID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	for i, token := range f.tokens {
+		if token.SessionID != sessionID {
+			continue
+		}
+		if token.AccessExpiresAt.After(now) {
+			f.denied[token.AccessTokenID] = true
+		}
+		if token.RevokedAt == nil {
+			f.tokens[i].RevokedAt = &now
+		}
+	}
+	return nil
+}
+
+func (f *fakeSessions) IsDenied(id string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.denied[id], nil
+}
+
+type fakeTwoFactors struct {
+	repository.TwoFactorRepository
+
+	mu            sync.Mutex
+	twoFactors    map[uint]domain.TwoFactor
+	recoveryCodes []domain.RecoveryCode
+}
+
+func newFakeTwoFactors() *fakeTwoFactors {
+	return &fakeTwoFactors{twoFactors: make(map[uint]domain.TwoFactor)}
+}
+
+func (f *fakeTwoFactors) FindByUser(userID uint) (*domain.TwoFactor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	twoFactor, ok := f.twoFactors[userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &twoFactor, nil
+}
+
+func (f *fakeTwoFactors) Save(twoFactor *domain.TwoFactor) (*domain.TwoFactor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if current, ok := f.twoFactors[twoFactor.UserID]; ok && current.EnabledAt != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	f.twoFactors[twoFactor.UserID] = *twoFactor
+	return twoFactor, nil
+}
+
+func (f *fakeTwoFactors) Enable(userID uint, codes []domain.RecoveryCode) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	twoFactor, ok := f.twoFactors[userID]
+	if !ok || twoFactor.EnabledAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	now := time.Now()
+	twoFactor.EnabledAt = &now
+	f.twoFactors[userID] = twoFactor
+	f.recoveryCodes = append(f.recoveryCodes, codes...)
+	return nil
+}
+
+func (f *fakeTwoFactors) UseStep(userID uint, step int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	twoFactor, ok := f.twoFactors[userID]
+	if !ok || twoFactor.LastStep >= step {
+		return gorm.ErrRecordNotFound
+	}
+	twoFactor.LastStep = step
+	f.twoFactors[userID] = twoFactor
+	return nil
+}
+
+func (f *fakeTwoFactors) UseRecoveryCode(userID uint, hash string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for i, code := range f.recoveryCodes {
+		if code.UserID == userID && code.Hash == hash && code.UsedAt == nil {
+			now := time.Now()
+			f.recoveryCodes[i].UsedAt = &now
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+type fakeIdentities struct {
+	repository.IdentityRepository
+
+	mu         sync.Mutex
+	identities []domain.Identity
+	states     map[string]domain.OIDCState
+}
+
+func newFakeIdentities() *fakeIdentities {
+	return &fakeIdentities{states: make(map[string]domain.OIDCState)}
+}
+
+func (f *fakeIdentities) Create(identity *domain.Identity) (*domain.Identity, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	identity.ID = uint(len(f.identities) + 1)
+	f.identities = append(f.identities, *identity)
+	return identity, nil
+}
+
+func (f *fakeIdentities) FindBySubject(provider string, subject string) (*domain.Identity, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, identity := range f.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return &identity, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (f *fakeIdentities) CreateState(state *domain.OIDCState) (*domain.OIDCState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.states[state.Hash] = *state
+	return state, nil
+}
+
+func (f *fakeIdentities) UseState(hash string, provider string) (*domain.OIDCState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	state, ok := f.states[hash]
+	if !ok || state.Provider != provider || state.ExpiresAt.Before(time.Now()) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	delete(f.states, hash)
+	return &state, nil
+}
+
+type fakeSafetyEvents struct {
+	mu     sync.Mutex
+	events []domain.SafetyEvent
+}
+
+func (f *fakeSafetyEvents) Create(event *domain.SafetyEvent) (*domain.SafetyEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = append(f.events, *event)
+	return event, nil
+}
+
+type fakeVentReplies struct {
+	mu      sync.Mutex
+	replies []domain.VentReply
+}
+
+func (f *fakeVentReplies) Create(reply *domain.VentReply) (*domain.VentReply, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.replies = append(f.replies, *reply)
+	return reply, nil
+}
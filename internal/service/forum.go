@@ -7,28 +7,34 @@ import (
 	"github.com/aternity/zense/internal/entity/domain"
 	"github.com/aternity/zense/internal/entity/web"
 	"github.com/aternity/zense/internal/repository"
+	"github.com/aternity/zense/internal/util"
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
 type ForumService interface {
 	Create(req web.ForumCreate) (*web.ForumResponse, error)
-	FindAll() ([]web.ForumResponse, error)
+	FindAll(req web.ForumFindAll) (*web.PageResponse[web.ForumResponse], error)
 	FindByID(req web.ForumFindByID) (*web.ForumResponse, error)
 	Update(req web.ForumUpdate) (*web.ForumResponse, error)
 	Delete(req web.ForumDelete) error
+	Restore(req web.ForumRestore) (*web.ForumResponse, error)
 	RemoveTopic(req web.ForumRemoveTopic) error
 }
 
 type forumService struct {
-	forumRepository repository.ForumRepository
-	topicRepository repository.TopicRepository
+	forumRepository    repository.ForumRepository
+	topicRepository    repository.TopicRepository
+	reactionRepository repository.ReactionRepository
+	pseudonyms         *util.Pseudonyms
 }
 
-func NewForumService(forumRepository repository.ForumRepository, topicRepository repository.TopicRepository) ForumService {
+func NewForumService(forumRepository repository.ForumRepository, topicRepository repository.TopicRepository, reactionRepository repository.ReactionRepository, pseudonyms *util.Pseudonyms) ForumService {
 	return &forumService{
-		forumRepository: forumRepository,
-		topicRepository: topicRepository,
+		forumRepository:    forumRepository,
+		topicRepository:    topicRepository,
+		reactionRepository: reactionRepository,
+		pseudonyms:         pseudonyms,
 	}
 }
 
@@ -46,10 +52,11 @@ func (s *forumService) Create(req web.ForumCreate) (*web.ForumResponse, error) {
 	}
 
 	forum := &domain.Forum{
-		UserID:  req.UserID,
-		Title:   req.Title,
-		Topics:  topics,
-		Content: req.Content,
+		UserID:    req.UserID,
+		Title:     req.Title,
+		Topics:    topics,
+		Content:   req.Content,
+		Anonymous: req.Anonymous,
 	}
 
 	forum, err := s.forumRepository.Create(forum)
@@ -73,18 +80,24 @@ func (s *forumService) Create(req web.ForumCreate) (*web.ForumResponse, error) {
 		Content:   forum.Content,
 		CreatedAt: &forum.CreatedAt,
 	}
+	s.anonymize(response, *forum, req.UserID, "")
 
 	return response, nil
 }
 
-func (s *forumService) FindAll() ([]web.ForumResponse, error) {
-	forums, err := s.forumRepository.FindAll()
+func (s *forumService) FindAll(req web.ForumFindAll) (*web.PageResponse[web.ForumResponse], error) {
+	filter := repository.ForumFilter{
+		TopicID:          req.TopicID,
+		UserID:           req.AuthorID,
+		ExcludeAnonymous: req.AuthorID != 0 && req.AuthorID != req.UserID && !isModerator(req.Role),
+	}
+
+	forums, err := s.forumRepository.FindAll(filter, pagination(req.PageQuery, req.Sort))
 	if err != nil {
-		return nil, err
+		return nil, pageError(err)
 	}
 
-	var responses []web.ForumResponse
-	for _, forum := range forums {
+	response := pageResponse(forums, req.PageQuery, func(forum domain.Forum) web.ForumResponse {
 		var topicsResponse []web.TopicResponse
 		for _, topic := range forum.Topics {
 			topicsResponse = append(topicsResponse, web.TopicResponse{
@@ -94,7 +107,7 @@ func (s *forumService) FindAll() ([]web.ForumResponse, error) {
 			})
 		}
 
-		responses = append(responses, web.ForumResponse{
+		response := web.ForumResponse{
 			ID:        forum.ID,
 			Title:     forum.Title,
 			Topics:    topicsResponse,
@@ -105,10 +118,27 @@ func (s *forumService) FindAll() ([]web.ForumResponse, error) {
 				ID:   forum.User.ID,
 				Name: forum.User.Name,
 			},
-		})
+		}
+		s.anonymize(&response, forum, req.UserID, req.Role)
+
+		return response
+	})
+
+	ids := make([]uint, 0, len(response.Data))
+	for _, forum := range response.Data {
+		ids = append(ids, forum.ID)
 	}
 
-	return responses, nil
+	reactions, err := reactionSummaries(s.reactionRepository, domain.ForumTarget, ids, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range response.Data {
+		response.Data[i].Reactions = reactions[response.Data[i].ID]
+	}
+
+	return response, nil
 }
 
 func (s *forumService) FindByID(req web.ForumFindByID) (*web.ForumResponse, error) {
@@ -117,27 +147,14 @@ func (s *forumService) FindByID(req web.ForumFindByID) (*web.ForumResponse, erro
 		return nil, err
 	}
 
-	var topics []web.TopicResponse
-	for _, topic := range forum.Topics {
-		topics = append(topics, web.TopicResponse{
-			ID:          topic.ID,
-			Name:        topic.Name,
-			Description: topic.Description,
-		})
-	}
+	response := forumResponse(*forum)
+	s.anonymize(response, *forum, req.UserID, req.Role)
 
-	response := &web.ForumResponse{
-		ID:        forum.ID,
-		Title:     forum.Title,
-		Topics:    topics,
-		Content:   forum.Content,
-		CreatedAt: &forum.CreatedAt,
-		UpdatedAt: &forum.UpdatedAt,
-		User: &web.UserResponse{
-			ID:   forum.User.ID,
-			Name: forum.User.Name,
-		},
+	reactions, err := reactionSummaries(s.reactionRepository, domain.ForumTarget, []uint{forum.ID}, req.UserID)
+	if err != nil {
+		return nil, err
 	}
+	response.Reactions = reactions[forum.ID]
 
 	return response, nil
 }
@@ -206,14 +223,32 @@ func (s *forumService) Delete(req web.ForumDelete) error {
 	}
 
 	if forum.UserID != req.UserID {
-		return echo.NewHTTPError(http.StatusForbidden, "user does not have permission to update this forum")
+		return echo.NewHTTPError(http.StatusForbidden, "user does not have permission to delete this forum")
 	}
 
-	if err := s.forumRepository.Delete(forum); err != nil {
-		return err
+	return s.forumRepository.Delete(forum)
+}
+
+// Restore brings back a deleted forum with its thread. Authors restore their
+// own forums, moderators restore any forum and the restore is logged.
+func (s *forumService) Restore(req web.ForumRestore) (*web.ForumResponse, error) {
+	forum, err := s.forumRepository.FindDeleted(req.ID)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	if forum.UserID != req.UserID && !isModerator(req.Role) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "user does not have permission to restore this forum")
+	}
+
+	if err := s.forumRepository.Restore(forum, restoreLog(req.UserID, forum.UserID, "forum", forum.ID)); err != nil {
+		return nil, err
+	}
+
+	response := forumResponse(*forum)
+	s.anonymize(response, *forum, req.UserID, req.Role)
+
+	return response, nil
 }
 
 func (s *forumService) RemoveTopic(req web.ForumRemoveTopic) error {
@@ -232,3 +267,43 @@ func (s *forumService) RemoveTopic(req web.ForumRemoveTopic) error {
 
 	return nil
 }
+
+// anonymize names the author of an anonymous forum by its pseudonym in the
+// thread and hides who they are from everyone but themselves and moderators.
+func (s *forumService) anonymize(response *web.ForumResponse, forum domain.Forum, userID uint, role domain.UserRole) {
+	if !forum.Anonymous {
+		return
+	}
+
+	response.Anonymous = true
+	response.Pseudonym = s.pseudonyms.Name(forum.ID, forum.UserID)
+
+	if forum.UserID != userID && !isModerator(role) {
+		response.UserID = 0
+		response.User = nil
+	}
+}
+
+func forumResponse(forum domain.Forum) *web.ForumResponse {
+	var topics []web.TopicResponse
+	for _, topic := range forum.Topics {
+		topics = append(topics, web.TopicResponse{
+			ID:          topic.ID,
+			Name:        topic.Name,
+			Description: topic.Description,
+		})
+	}
+
+	return &web.ForumResponse{
+		ID:        forum.ID,
+		Title:     forum.Title,
+		Topics:    topics,
+		Content:   forum.Content,
+		CreatedAt: &forum.CreatedAt,
+		UpdatedAt: &forum.UpdatedAt,
+		User: &web.UserResponse{
+			ID:   forum.User.ID,
+			Name: forum.User.Name,
+		},
+	}
+}
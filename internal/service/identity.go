@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/oidc"
+	"github.com/aternity/zense/internal/repository"
+	"github.com/aternity/zense/internal/util"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+	"gorm.io/gorm"
+)
+
+type IdentityService interface {
+	FindAll(req web.IdentityFindAll) ([]web.IdentityResponse, error)
+	Connect(ctx context.Context, req web.OIDCStart) (*web.OIDCStartResponse, error)
+	ConnectCallback(ctx context.Context, req web.OIDCCallback) (*web.IdentityResponse, error)
+	Delete(req web.IdentityDelete) error
+}
+
+// oidcStateTTL is how long a user has to sign in at the provider.
+const oidcStateTTL = 10 * time.Minute
+
+type identityService struct {
+	identityRepository repository.IdentityRepository
+	userRepository     repository.UserRepository
+	providers          oidc.Providers
+}
+
+func NewIdentityService(identityRepository repository.IdentityRepository, userRepository repository.UserRepository, providers oidc.Providers) IdentityService {
+	return &identityService{
+		identityRepository: identityRepository,
+		userRepository:     userRepository,
+		providers:          providers,
+	}
+}
+
+func (s *identityService) FindAll(req web.IdentityFindAll) ([]web.IdentityResponse, error) {
+	identities, err := s.identityRepository.FindByUser(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]web.IdentityResponse, 0, len(identities))
+	for _, identity := range identities {
+		response = append(response, identityResponse(identity))
+	}
+
+	return response, nil
+}
+
+// Connect starts a sign in at the provider to connect it to the user.
+func (s *identityService) Connect(ctx context.Context, req web.OIDCStart) (*web.OIDCStartResponse, error) {
+	return startOIDC(ctx, s.identityRepository, s.providers, req.Provider, &req.UserID)
+}
+
+// ConnectCallback connects the identity the user signed in with at the
+// provider.
+func (s *identityService) ConnectCallback(ctx context.Context, req web.OIDCCallback) (*web.IdentityResponse, error) {
+	state, identity, err := finishOIDC(ctx, s.identityRepository, s.providers, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if state.UserID == nil || *state.UserID != req.UserID {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid or expired state")
+	}
+
+	linked, err := s.identityRepository.FindBySubject(req.Provider, identity.Subject)
+	if err == nil {
+		if linked.UserID == req.UserID {
+			return nil, echo.NewHTTPError(http.StatusConflict, "this identity is already connected")
+		}
+		return nil, echo.NewHTTPError(http.StatusConflict, "this identity is connected to another account")
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	created, err := s.identityRepository.Create(&domain.Identity{
+		UserID:   req.UserID,
+		Provider: req.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := identityResponse(*created)
+	return &response, nil
+}
+
+// Delete disconnects an identity. The last one of a user without a password
+// is kept, or they could not sign in anymore.
+func (s *identityService) Delete(req web.IdentityDelete) error {
+	identity, err := s.identityRepository.FindByID(req.ID)
+	if err != nil {
+		return err
+	}
+
+	if identity.UserID != req.UserID {
+		return gorm.ErrRecordNotFound
+	}
+
+	user, err := s.userRepository.FindByID(req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user.Password == "" {
+		identities, err := s.identityRepository.FindByUser(req.UserID)
+		if err != nil {
+			return err
+		}
+
+		if len(identities) <= 1 {
+			return echo.NewHTTPError(http.StatusConflict, "set a password before disconnecting your last identity")
+		}
+	}
+
+	return s.identityRepository.Delete(identity.ID)
+}
+
+// oidcProviders returns the names of the configured providers.
+func oidcProviders(providers oidc.Providers) []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// startOIDC stores a new state with its nonce and PKCE verifier and returns
+// where to send the user.
+func startOIDC(ctx context.Context, identityRepository repository.IdentityRepository, providers oidc.Providers, name string, userID *uint) (*web.OIDCStartResponse, error) {
+	provider, ok := providers[name]
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusNotFound, "provider not found")
+	}
+
+	state, err := util.RandomToken(32)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to generate state")
+	}
+
+	nonce, err := util.RandomToken(16)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to generate state")
+	}
+
+	verifier := oauth2.GenerateVerifier()
+
+	url, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		logrus.WithError(err).WithField("provider", name).Error("failed to reach oidc provider")
+		return nil, echo.NewHTTPError(http.StatusBadGateway, "the provider is not available")
+	}
+
+	if _, err := identityRepository.CreateState(&domain.OIDCState{
+		Hash:      util.HashToken(state),
+		Provider:  name,
+		UserID:    userID,
+		Nonce:     nonce,
+		Verifier:  verifier,
+		ExpiresAt: time.Now().Add(oidcStateTTL),
+	}); err != nil {
+		return nil, err
+	}
+
+	return &web.OIDCStartResponse{AuthorizationURL: url}, nil
+}
+
+// finishOIDC consumes the state and redeems the code at the provider.
+func finishOIDC(ctx context.Context, identityRepository repository.IdentityRepository, providers oidc.Providers, req web.OIDCCallback) (*domain.OIDCState, *oidc.Identity, error) {
+	provider, ok := providers[req.Provider]
+	if !ok {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, "provider not found")
+	}
+
+	state, err := identityRepository.UseState(util.HashToken(req.State), req.Provider)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "invalid or expired state")
+		}
+		return nil, nil, err
+	}
+
+	identity, err := provider.Exchange(ctx, req.Code, state.Verifier, state.Nonce)
+	if err != nil {
+		logrus.WithError(err).WithField("provider", req.Provider).Warn("oidc sign in failed")
+		return nil, nil, echo.NewHTTPError(http.StatusUnauthorized, "sign in with the provider failed")
+	}
+
+	return state, identity, nil
+}
+
+func identityResponse(identity domain.Identity) web.IdentityResponse {
+	return web.IdentityResponse{
+		ID:        identity.ID,
+		Provider:  identity.Provider,
+		Email:     identity.Email,
+		CreatedAt: identity.CreatedAt,
+	}
+}
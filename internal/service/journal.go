@@ -1,29 +1,49 @@
 package service
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/aternity/zense/internal/entity/domain"
 	"github.com/aternity/zense/internal/entity/web"
 	"github.com/aternity/zense/internal/repository"
 	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
 )
 
 type JournalService interface {
 	Create(req web.JournalCreate) (*web.JournalResponse, error)
-	FindAll() ([]web.JournalResponse, error)
+	FindAll(req web.JournalFindAll) (*web.PageResponse[web.JournalResponse], error)
+	FindByUser(req web.JournalFindByUser) (*web.PageResponse[web.JournalResponse], error)
 	FindByID(req web.JournalFindByID) (*web.JournalResponse, error)
 	Update(req web.JournalUpdate) (*web.JournalResponse, error)
 	Delete(req web.JournalDelete) error
+	Restore(req web.JournalRestore) (*web.JournalResponse, error)
+	MoodInsights(req web.MoodInsights) (*web.MoodInsightsResponse, error)
+	Export(req web.JournalExport, w io.Writer) error
+	Import(req web.JournalImport, r io.Reader) (*web.JournalImportResponse, error)
 }
 
+// defaultInsightDays is the number of days covered by the mood insights when
+// the request does not start the range, and maxInsightDays the most a request
+// can ask for.
+const (
+	defaultInsightDays = 90
+	maxInsightDays     = 366
+)
+
 type journalService struct {
-	journalRepository repository.JournalRepository
+	journalRepository  repository.JournalRepository
+	reactionRepository repository.ReactionRepository
 }
 
-func NewJournalService(journalRepository repository.JournalRepository) JournalService {
+func NewJournalService(journalRepository repository.JournalRepository, reactionRepository repository.ReactionRepository) JournalService {
 	return &journalService{
-		journalRepository: journalRepository,
+		journalRepository:  journalRepository,
+		reactionRepository: reactionRepository,
 	}
 }
 
@@ -52,30 +72,42 @@ func (s *journalService) Create(req web.JournalCreate) (*web.JournalResponse, er
 	return response, nil
 }
 
-func (s *journalService) FindAll() ([]web.JournalResponse, error) {
-	journals, err := s.journalRepository.FindAll()
+func (s *journalService) FindAll(req web.JournalFindAll) (*web.PageResponse[web.JournalResponse], error) {
+	filter, err := journalFilter(req.JournalFilter)
 	if err != nil {
 		return nil, err
 	}
 
-	var responses []web.JournalResponse
-	for _, journal := range journals {
-		response := web.JournalResponse{
-			ID:         journal.ID,
-			Mood:       journal.Mood,
-			Content:    journal.Content,
-			Visibility: journal.Visibility,
-			CreatedAt:  &journal.CreatedAt,
-			UpdatedAt:  &journal.UpdatedAt,
-			User: &web.UserResponse{
-				ID:   journal.User.ID,
-				Name: journal.User.Name,
-			},
-		}
-		responses = append(responses, response)
+	journals, err := s.journalRepository.FindAll(req.UserID, filter, pagination(req.PageQuery, req.Sort))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	response := pageResponse(journals, req.PageQuery, journalResponse)
+	if err := s.withReactions(response.Data, req.UserID); err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (s *journalService) FindByUser(req web.JournalFindByUser) (*web.PageResponse[web.JournalResponse], error) {
+	filter, err := journalFilter(req.JournalFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	journals, err := s.journalRepository.FindByUser(req.OwnerID, req.UserID, filter, pagination(req.PageQuery, req.Sort))
+	if err != nil {
+		return nil, pageError(err)
 	}
 
-	return responses, nil
+	response := pageResponse(journals, req.PageQuery, journalResponse)
+	if err := s.withReactions(response.Data, req.UserID); err != nil {
+		return nil, err
+	}
+
+	return response, nil
 }
 
 func (s *journalService) FindByID(req web.JournalFindByID) (*web.JournalResponse, error) {
@@ -84,20 +116,17 @@ func (s *journalService) FindByID(req web.JournalFindByID) (*web.JournalResponse
 		return nil, err
 	}
 
-	response := &web.JournalResponse{
-		ID:         journal.ID,
-		Mood:       journal.Mood,
-		Content:    journal.Content,
-		Visibility: journal.Visibility,
-		CreatedAt:  &journal.CreatedAt,
-		UpdatedAt:  &journal.UpdatedAt,
-		User: &web.UserResponse{
-			ID:   journal.User.ID,
-			Name: journal.User.Name,
-		},
+	// Someone else's private journal must look like it does not exist.
+	if journal.Visibility != domain.PublicJournal && journal.UserID != req.UserID {
+		return nil, gorm.ErrRecordNotFound
 	}
 
-	return response, nil
+	responses := []web.JournalResponse{journalResponse(*journal)}
+	if err := s.withReactions(responses, req.UserID); err != nil {
+		return nil, err
+	}
+
+	return &responses[0], nil
 }
 
 func (s *journalService) Update(req web.JournalUpdate) (*web.JournalResponse, error) {
@@ -145,9 +174,236 @@ func (s *journalService) Delete(req web.JournalDelete) error {
 		return echo.NewHTTPError(http.StatusForbidden, "user does not have permission to delete this journal")
 	}
 
-	if err := s.journalRepository.Delete(journal); err != nil {
+	return s.journalRepository.Delete(journal)
+}
+
+// Restore brings back a deleted journal. Authors restore their own journals,
+// moderators restore any public journal and the restore is logged.
+func (s *journalService) Restore(req web.JournalRestore) (*web.JournalResponse, error) {
+	journal, err := s.journalRepository.FindDeleted(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if journal.UserID != req.UserID {
+		// Someone else's private journal must look like it does not exist.
+		if journal.Visibility != domain.PublicJournal {
+			return nil, gorm.ErrRecordNotFound
+		}
+
+		if !isModerator(req.Role) {
+			return nil, echo.NewHTTPError(http.StatusForbidden, "user does not have permission to restore this journal")
+		}
+	}
+
+	if err := s.journalRepository.Restore(journal, restoreLog(req.UserID, journal.UserID, "journal", journal.ID)); err != nil {
+		return nil, err
+	}
+
+	response := journalResponse(*journal)
+	return &response, nil
+}
+
+// withReactions attaches the reactions of the public journals among
+// responses, private journals cannot receive any.
+func (s *journalService) withReactions(responses []web.JournalResponse, userID uint) error {
+	var ids []uint
+	for _, journal := range responses {
+		if journal.Visibility == domain.PublicJournal {
+			ids = append(ids, journal.ID)
+		}
+	}
+
+	reactions, err := reactionSummaries(s.reactionRepository, domain.JournalTarget, ids, userID)
+	if err != nil {
 		return err
 	}
 
+	for i := range responses {
+		responses[i].Reactions = reactions[responses[i].ID]
+	}
+
 	return nil
 }
+
+func journalResponse(journal domain.Journal) web.JournalResponse {
+	return web.JournalResponse{
+		ID:         journal.ID,
+		Mood:       journal.Mood,
+		Content:    journal.Content,
+		Visibility: journal.Visibility,
+		CreatedAt:  &journal.CreatedAt,
+		UpdatedAt:  &journal.UpdatedAt,
+		User: &web.UserResponse{
+			ID:   journal.User.ID,
+			Name: journal.User.Name,
+		},
+	}
+}
+
+// journalFilter parses the inclusive date range of a listing into the
+// repository filter.
+func journalFilter(req web.JournalFilter) (repository.JournalFilter, error) {
+	filter := repository.JournalFilter{
+		Mood: req.Mood,
+	}
+
+	if req.From != "" {
+		from, err := time.Parse(time.DateOnly, req.From)
+		if err != nil {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid from date")
+		}
+		filter.From = from
+	}
+
+	if req.To != "" {
+		to, err := time.Parse(time.DateOnly, req.To)
+		if err != nil {
+			return filter, echo.NewHTTPError(http.StatusBadRequest, "invalid to date")
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	return filter, nil
+}
+
+func (s *journalService) MoodInsights(req web.MoodInsights) (*web.MoodInsightsResponse, error) {
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+
+	// LoadLocation reads "" as UTC and "Local" as the timezone of the server,
+	// neither is an IANA name and Postgres does not know the latter.
+	if timezone == "Local" || timezone == "" {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "unknown timezone")
+	}
+
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "unknown timezone")
+	}
+
+	now := time.Now().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+
+	to := today
+	if req.To != "" {
+		date, err := time.ParseInLocation(time.DateOnly, req.To, location)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid to date")
+		}
+		to = date
+	}
+
+	from := to.AddDate(0, 0, 1-defaultInsightDays)
+	if req.From != "" {
+		date, err := time.ParseInLocation(time.DateOnly, req.From, location)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid from date")
+		}
+		from = date
+	}
+
+	if from.After(to) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "from date is after to date")
+	}
+
+	if to.After(from.AddDate(0, 0, maxInsightDays-1)) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("range is longer than %d days", maxInsightDays))
+	}
+
+	moods := repository.MoodRange{
+		UserID:   req.UserID,
+		From:     from,
+		To:       to.AddDate(0, 0, 1),
+		Timezone: location.String(),
+	}
+
+	response := &web.MoodInsightsResponse{
+		From:     from.Format(time.DateOnly),
+		To:       to.Format(time.DateOnly),
+		Timezone: location.String(),
+		Scores:   []web.MoodScore{},
+		Weekdays: []web.WeekdayMood{},
+	}
+
+	for _, period := range []struct {
+		name         string
+		distribution *[]web.MoodDistribution
+	}{
+		{"day", &response.Daily},
+		{"week", &response.Weekly},
+		{"month", &response.Monthly},
+	} {
+		counts, err := s.journalRepository.CountMoods(moods, period.name)
+		if err != nil {
+			return nil, err
+		}
+		*period.distribution = moodDistributions(counts)
+	}
+
+	scores, err := s.journalRepository.ScoreMoods(moods)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, score := range scores {
+		response.Scores = append(response.Scores, web.MoodScore{
+			Date:    score.Day.Format(time.DateOnly),
+			Score:   score.Score,
+			Entries: score.Entries,
+		})
+	}
+
+	weekdays, err := s.journalRepository.CountMoodsByWeekday(moods)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, weekday := range weekdays {
+		response.Weekdays = append(response.Weekdays, web.WeekdayMood{
+			Weekday: strings.ToLower(time.Weekday(weekday.Weekday % 7).String()),
+			Mood:    weekday.Mood,
+			Entries: weekday.Total,
+		})
+	}
+
+	streaks, err := s.journalRepository.FindStreaks(req.UserID, location.String())
+	if err != nil {
+		return nil, err
+	}
+
+	response.Streaks.Longest = streaks.Longest.Days
+	if streaks.Latest.Days > 0 {
+		lastEntry := streaks.Latest.LastDay.Format(time.DateOnly)
+		response.Streaks.LastEntry = lastEntry
+
+		if lastEntry >= today.AddDate(0, 0, -1).Format(time.DateOnly) {
+			response.Streaks.Current = streaks.Latest.Days
+		}
+	}
+
+	return response, nil
+}
+
+// moodDistributions groups the mood counts of the repository by period,
+// keeping their order.
+func moodDistributions(counts []repository.MoodCount) []web.MoodDistribution {
+	distributions := []web.MoodDistribution{}
+	for _, count := range counts {
+		period := count.Period.Format(time.DateOnly)
+		if len(distributions) == 0 || distributions[len(distributions)-1].Period != period {
+			distributions = append(distributions, web.MoodDistribution{
+				Period: period,
+				Counts: map[domain.JournalMood]int64{},
+			})
+		}
+
+		distribution := &distributions[len(distributions)-1]
+		distribution.Counts[count.Mood] = count.Total
+		distribution.Total += count.Total
+	}
+
+	return distributions
+}
@@ -0,0 +1,242 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	journalMoods        = []domain.JournalMood{domain.Happy, domain.Good, domain.Normal, domain.Sad, domain.Angry}
+	journalVisibilities = []domain.JournalVisibility{domain.PrivateJournal, domain.PublicJournal}
+	journalCSVHeader    = []string{"id", "created_at", "updated_at", "mood", "visibility", "content"}
+)
+
+// Export writes every journal of the user to w, oldest first, without
+// loading them all in memory.
+func (s *journalService) Export(req web.JournalExport, w io.Writer) error {
+	location := time.UTC
+	if req.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(req.Timezone); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "unknown timezone")
+		}
+	}
+
+	switch req.Format {
+	case "json":
+		return s.exportJSON(req.UserID, w)
+	case "csv":
+		return s.exportCSV(req.UserID, w)
+	case "markdown":
+		return s.exportMarkdown(req.UserID, location, w)
+	}
+
+	return echo.NewHTTPError(http.StatusBadRequest, "unsupported export format")
+}
+
+// Import validates every entry of a JSON or CSV export before creating any
+// of them. Entries the user already has are skipped.
+func (s *journalService) Import(req web.JournalImport, r io.Reader) (*web.JournalImportResponse, error) {
+	var entries []web.JournalEntry
+	switch req.Format {
+	case "json":
+		if err := json.NewDecoder(r).Decode(&entries); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid JSON export: "+err.Error())
+		}
+	case "csv":
+		var err error
+		if entries, err = readJournalCSV(r); err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid CSV export: "+err.Error())
+		}
+	default:
+		return nil, echo.NewHTTPError(http.StatusUnsupportedMediaType, "unsupported import format")
+	}
+
+	journals := make([]domain.Journal, 0, len(entries))
+	for i, entry := range entries {
+		if !slices.Contains(journalMoods, entry.Mood) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("entry %d: invalid mood %q", i+1, entry.Mood))
+		}
+		if !slices.Contains(journalVisibilities, entry.Visibility) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("entry %d: invalid visibility %q", i+1, entry.Visibility))
+		}
+		if entry.Content == "" {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("entry %d: missing content", i+1))
+		}
+		if entry.CreatedAt.IsZero() {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("entry %d: missing created_at", i+1))
+		}
+
+		// PostgreSQL keeps microseconds, truncating keeps re-imports
+		// comparable with the stored journals.
+		createdAt := entry.CreatedAt.Truncate(time.Microsecond)
+		updatedAt := entry.UpdatedAt.Truncate(time.Microsecond)
+		if updatedAt.Before(createdAt) {
+			updatedAt = createdAt
+		}
+
+		journals = append(journals, domain.Journal{
+			Mood:       entry.Mood,
+			Visibility: entry.Visibility,
+			Content:    entry.Content,
+			CreatedAt:  createdAt,
+			UpdatedAt:  updatedAt,
+		})
+	}
+
+	imported, err := s.journalRepository.Import(req.UserID, journals)
+	if err != nil {
+		return nil, err
+	}
+
+	return &web.JournalImportResponse{
+		Imported: imported,
+		Skipped:  len(journals) - imported,
+	}, nil
+}
+
+// exportJSON writes a JSON array with one entry per line.
+func (s *journalService) exportJSON(userID uint, w io.Writer) error {
+	buffer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffer)
+
+	buffer.WriteString("[\n")
+	first := true
+	err := s.journalRepository.Each(userID, func(journal domain.Journal) error {
+		if !first {
+			buffer.WriteString(",")
+		}
+		first = false
+
+		return encoder.Encode(journalEntry(journal))
+	})
+	if err != nil {
+		return err
+	}
+	buffer.WriteString("]\n")
+
+	return buffer.Flush()
+}
+
+func (s *journalService) exportCSV(userID uint, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(journalCSVHeader); err != nil {
+		return err
+	}
+
+	err := s.journalRepository.Each(userID, func(journal domain.Journal) error {
+		return writer.Write([]string{
+			fmt.Sprint(journal.ID),
+			journal.CreatedAt.Format(time.RFC3339Nano),
+			journal.UpdatedAt.Format(time.RFC3339Nano),
+			string(journal.Mood),
+			string(journal.Visibility),
+			journal.Content,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// exportMarkdown writes one section per day, with the entries of the day in
+// the order they were written.
+func (s *journalService) exportMarkdown(userID uint, location *time.Location, w io.Writer) error {
+	buffer := bufio.NewWriter(w)
+	buffer.WriteString("# Journals\n")
+
+	day := ""
+	err := s.journalRepository.Each(userID, func(journal domain.Journal) error {
+		createdAt := journal.CreatedAt.In(location)
+		if date := createdAt.Format(time.DateOnly); date != day {
+			day = date
+			fmt.Fprintf(buffer, "\n## %s\n", createdAt.Format("Monday, 2 January 2006"))
+		}
+
+		fmt.Fprintf(buffer, "\n### %s · %s · %s\n\n%s\n", createdAt.Format("15:04"), journal.Mood, journal.Visibility, strings.TrimSpace(journal.Content))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return buffer.Flush()
+}
+
+// readJournalCSV reads a CSV export. Columns are matched by their header, so
+// they can come in any order and id and updated_at may be left out.
+func readJournalCSV(r io.Reader) ([]web.JournalEntry, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	for _, name := range []string{"created_at", "mood", "visibility", "content"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing column %s", name)
+		}
+	}
+
+	var entries []web.JournalEntry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		entry := web.JournalEntry{
+			Mood:       domain.JournalMood(record[columns["mood"]]),
+			Visibility: domain.JournalVisibility(record[columns["visibility"]]),
+			Content:    record[columns["content"]],
+		}
+
+		if entry.CreatedAt, err = time.Parse(time.RFC3339Nano, record[columns["created_at"]]); err != nil {
+			return nil, fmt.Errorf("line %d: invalid created_at", line)
+		}
+
+		if i, ok := columns["updated_at"]; ok && record[i] != "" {
+			if entry.UpdatedAt, err = time.Parse(time.RFC3339Nano, record[i]); err != nil {
+				return nil, fmt.Errorf("line %d: invalid updated_at", line)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func journalEntry(journal domain.Journal) web.JournalEntry {
+	return web.JournalEntry{
+		ID:         journal.ID,
+		Mood:       journal.Mood,
+		Visibility: journal.Visibility,
+		Content:    journal.Content,
+		CreatedAt:  journal.CreatedAt,
+		UpdatedAt:  journal.UpdatedAt,
+	}
+}
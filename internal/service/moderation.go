@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/repository"
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+type ModerationService interface {
+	FindPendingComments(req web.CommentFindPending) (*web.PageResponse[web.CommentResponse], error)
+	ApproveComment(req web.CommentApprove) error
+	RejectComment(req web.CommentReject) error
+	FindLogs(req web.ModerationLogFindAll) (*web.PageResponse[web.ModerationLogResponse], error)
+}
+
+type moderationService struct {
+	commentRepository       repository.CommentRepository
+	moderationLogRepository repository.ModerationLogRepository
+}
+
+func NewModerationService(commentRepository repository.CommentRepository, moderationLogRepository repository.ModerationLogRepository) ModerationService {
+	return &moderationService{
+		commentRepository:       commentRepository,
+		moderationLogRepository: moderationLogRepository,
+	}
+}
+
+func (s *moderationService) FindPendingComments(req web.CommentFindPending) (*web.PageResponse[web.CommentResponse], error) {
+	// The queue is worked oldest first unless asked otherwise.
+	if req.Order == "" {
+		req.Order = "asc"
+	}
+
+	comments, err := s.commentRepository.FindByVisibility(domain.ReviewComment, pagination(req.PageQuery, req.Sort))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	return pageResponse(comments, req.PageQuery, commentResponse), nil
+}
+
+func (s *moderationService) ApproveComment(req web.CommentApprove) error {
+	return s.moderateComment(req.ID, req.UserID, domain.PublicComment, domain.ApproveAction, req.Reason)
+}
+
+func (s *moderationService) RejectComment(req web.CommentReject) error {
+	return s.moderateComment(req.ID, req.UserID, domain.RejectedComment, domain.RejectAction, req.Reason)
+}
+
+func (s *moderationService) FindLogs(req web.ModerationLogFindAll) (*web.PageResponse[web.ModerationLogResponse], error) {
+	logs, err := s.moderationLogRepository.FindAll(pagination(req.PageQuery, "created_at"))
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	response := pageResponse(logs, req.PageQuery, func(log domain.ModerationLog) web.ModerationLogResponse {
+		return web.ModerationLogResponse{
+			ID:          log.ID,
+			ModeratorID: log.ModeratorID,
+			TargetType:  log.TargetType,
+			TargetID:    log.TargetID,
+			Action:      log.Action,
+			Reason:      log.Reason,
+			CreatedAt:   &log.CreatedAt,
+		}
+	})
+
+	return response, nil
+}
+
+func (s *moderationService) moderateComment(id uint, moderatorID uint, visibility domain.CommentVisibility, action domain.ModerationAction, reason string) error {
+	comment, err := s.commentRepository.FindByID(id)
+	if err != nil {
+		return err
+	}
+
+	if comment.Visibility != domain.ReviewComment {
+		return echo.NewHTTPError(http.StatusConflict, "comment is not waiting for review")
+	}
+
+	comment.Visibility = visibility
+	if err := s.moderationLogRepository.ModerateComment(comment, &domain.ModerationLog{
+		ModeratorID: moderatorID,
+		TargetType:  "comment",
+		TargetID:    comment.ID,
+		Action:      action,
+		Reason:      reason,
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return echo.NewHTTPError(http.StatusConflict, "comment is not waiting for review")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func isModerator(role domain.UserRole) bool {
+	return role == domain.ModeratorUser || role == domain.AdminUser
+}
+
+// restoreLog returns the moderation log of a restore by userID of content
+// owned by ownerID, nil when authors restore their own content.
+func restoreLog(userID uint, ownerID uint, targetType string, targetID uint) *domain.ModerationLog {
+	if userID == ownerID {
+		return nil
+	}
+
+	return &domain.ModerationLog{
+		ModeratorID: userID,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Action:      domain.RestoreAction,
+	}
+}
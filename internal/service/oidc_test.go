@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/oidc"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testClientID = "zense"
+
+// testIssuer is an OpenID provider serving discovery, its keys and a token
+// endpoint that returns an ID token with the claims the test sets.
+type testIssuer struct {
+	*httptest.Server
+	key *ecdsa.PrivateKey
+
+	mu     sync.Mutex
+	claims jwt.MapClaims
+}
+
+func newTestIssuer(t *testing.T) *testIssuer {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() = %v", err)
+	}
+
+	issuer := &testIssuer{key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 issuer.URL,
+			"authorization_endpoint": issuer.URL + "/authorize",
+			"token_endpoint":         issuer.URL + "/token",
+			"jwks_uri":               issuer.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		coordinate := func(n interface{ FillBytes([]byte) []byte }) string {
+			return base64.RawURLEncoding.EncodeToString(n.FillBytes(make([]byte, 32)))
+		}
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "EC",
+			"kid": "test",
+			"use": "sig",
+			"crv": "P-256",
+			"x":   coordinate(key.X),
+			"y":   coordinate(key.Y),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mu.Lock()
+		token := jwt.NewWithClaims(jwt.SigningMethodES256, issuer.claims)
+		issuer.mu.Unlock()
+		token.Header["kid"] = "test"
+
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, map[string]any{"access_token": "access", "token_type": "Bearer", "expires_in": 3600, "id_token": idToken})
+	})
+
+	issuer.Server = httptest.NewServer(mux)
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// signIn starts a sign in at the issuer and comes back with an ID token for
+// user@example.com, changed by edit first.
+func (i *testIssuer) signIn(t *testing.T, a *testAuth, edit func(jwt.MapClaims)) (*web.UserAuth, error) {
+	t.Helper()
+	ctx := context.Background()
+
+	start, err := a.service.StartOIDC(ctx, web.OIDCStart{Provider: "test"})
+	if err != nil {
+		t.Fatalf("StartOIDC() = %v", err)
+	}
+	authorization, err := url.Parse(start.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("Parse(%s) = %v", start.AuthorizationURL, err)
+	}
+	query := authorization.Query()
+
+	claims := jwt.MapClaims{
+		"iss":            i.URL,
+		"aud":            testClientID,
+		"sub":            "subject",
+		"email":          "user@example.com",
+		"email_verified": true,
+		"nonce":          query.Get("nonce"),
+		"iat":            time.Now().Unix(),
+		"exp":            time.Now().Add(time.Minute).Unix(),
+	}
+	if edit != nil {
+		edit(claims)
+	}
+	i.mu.Lock()
+	i.claims = claims
+	i.mu.Unlock()
+
+	auth, challenge, err := a.service.LoginOIDC(ctx, web.OIDCCallback{Provider: "test", Code: "code", State: query.Get("state")})
+	if challenge != nil {
+		t.Fatal("LoginOIDC() returned a two-factor challenge")
+	}
+	return auth, err
+}
+
+func newTestOIDC(t *testing.T) (*testAuth, *testIssuer, *fakeIdentities) {
+	t.Helper()
+	issuer := newTestIssuer(t)
+	identities := newFakeIdentities()
+	providers := oidc.NewProviders([]oidc.Config{{
+		Name:        "test",
+		Issuer:      issuer.URL,
+		ClientID:    testClientID,
+		RedirectURL: "http://app.example.com/callback",
+	}})
+	return newTestAuth(t, providers, identities), issuer, identities
+}
+
+func TestLoginOIDCLinksVerifiedAccount(t *testing.T) {
+	a, issuer, identities := newTestOIDC(t)
+
+	auth, err := issuer.signIn(t, a, nil)
+	if err != nil {
+		t.Fatalf("LoginOIDC() = %v", err)
+	}
+	if auth.ID != 1 {
+		t.Fatalf("signed in as user %d, want the account with the email", auth.ID)
+	}
+
+	identity, err := identities.FindBySubject("test", "subject")
+	if err != nil || identity.UserID != 1 {
+		t.Fatalf("FindBySubject() = %+v, %v, want an identity of user 1", identity, err)
+	}
+
+	// Once linked, the identity signs in to the account whatever its email.
+	auth, err = issuer.signIn(t, a, func(claims jwt.MapClaims) { claims["email"] = "changed@example.com" })
+	if err != nil || auth.ID != 1 {
+		t.Fatalf("LoginOIDC() with the linked identity = %+v, %v", auth, err)
+	}
+}
+
+func TestLoginOIDCRefusesUnverifiedEmail(t *testing.T) {
+	a, issuer, identities := newTestOIDC(t)
+
+	_, err := issuer.signIn(t, a, func(claims jwt.MapClaims) { claims["email_verified"] = false })
+	wantStatus(t, err, http.StatusForbidden)
+
+	if len(identities.identities) != 0 {
+		t.Fatal("an identity with an unverified email was linked")
+	}
+}
+
+func TestLoginOIDCRefusesUnverifiedAccount(t *testing.T) {
+	a, issuer, identities := newTestOIDC(t)
+	user := a.users.users[1]
+	user.VerifiedAt = nil
+	a.users.users[1] = user
+
+	_, err := issuer.signIn(t, a, nil)
+	wantStatus(t, err, http.StatusConflict)
+
+	if len(identities.identities) != 0 {
+		t.Fatal("an identity was linked to an unverified account")
+	}
+}
+
+func TestLoginOIDCRefusesInvalidIDToken(t *testing.T) {
+	for name, edit := range map[string]func(jwt.MapClaims){
+		"nonce mismatch": func(claims jwt.MapClaims) { claims["nonce"] = "another nonce" },
+		"wrong audience": func(claims jwt.MapClaims) { claims["aud"] = "another client" },
+		"wrong issuer":   func(claims jwt.MapClaims) { claims["iss"] = "https://issuer.example.com" },
+		"expired":        func(claims jwt.MapClaims) { claims["exp"] = time.Now().Add(-time.Hour).Unix() },
+	} {
+		t.Run(name, func(t *testing.T) {
+			a, issuer, identities := newTestOIDC(t)
+
+			_, err := issuer.signIn(t, a, edit)
+			wantStatus(t, err, http.StatusUnauthorized)
+
+			if len(identities.identities) != 0 {
+				t.Fatal("an identity was linked from an invalid ID token")
+			}
+		})
+	}
+}
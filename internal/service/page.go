@@ -0,0 +1,49 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/repository"
+	"github.com/labstack/echo/v4"
+)
+
+func pagination(query web.PageQuery, sort string) repository.Pagination {
+	return repository.Pagination{
+		Cursor: query.Cursor,
+		Page:   query.Page,
+		Limit:  query.Limit,
+		Sort:   sort,
+		Order:  query.Order,
+	}
+}
+
+// pageResponse wraps one repository page in the response envelope.
+func pageResponse[T any, R any](page *repository.Page[T], query web.PageQuery, fn func(T) R) *web.PageResponse[R] {
+	limit := query.Limit
+	if limit <= 0 {
+		limit = repository.DefaultLimit
+	}
+
+	response := &web.PageResponse[R]{
+		Data:       make([]R, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+		Page:       query.Page,
+		Limit:      limit,
+	}
+
+	for _, item := range page.Items {
+		response.Data = append(response.Data, fn(item))
+	}
+
+	return response
+}
+
+func pageError(err error) error {
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		return echo.NewHTTPError(http.StatusBadRequest, "invalid cursor")
+	}
+	return err
+}
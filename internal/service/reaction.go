@@ -0,0 +1,134 @@
+package service
+
+import (
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/repository"
+	"gorm.io/gorm"
+)
+
+type ReactionService interface {
+	Create(req web.ReactionCreate) (*web.ReactionSummary, error)
+	Delete(req web.ReactionDelete) error
+}
+
+type reactionService struct {
+	reactionRepository repository.ReactionRepository
+	forumRepository    repository.ForumRepository
+	commentRepository  repository.CommentRepository
+	journalRepository  repository.JournalRepository
+}
+
+func NewReactionService(
+	reactionRepository repository.ReactionRepository,
+	forumRepository repository.ForumRepository,
+	commentRepository repository.CommentRepository,
+	journalRepository repository.JournalRepository,
+) ReactionService {
+	return &reactionService{
+		reactionRepository: reactionRepository,
+		forumRepository:    forumRepository,
+		commentRepository:  commentRepository,
+		journalRepository:  journalRepository,
+	}
+}
+
+func (s *reactionService) Create(req web.ReactionCreate) (*web.ReactionSummary, error) {
+	if err := s.visible(req.TargetType, req.TargetID, req.UserID); err != nil {
+		return nil, err
+	}
+
+	reaction := &domain.Reaction{
+		UserID:     req.UserID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Type:       req.Type,
+	}
+
+	if _, err := s.reactionRepository.Create(reaction); err != nil {
+		return nil, err
+	}
+
+	summaries, err := reactionSummaries(s.reactionRepository, req.TargetType, []uint{req.TargetID}, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return summaries[req.TargetID], nil
+}
+
+func (s *reactionService) Delete(req web.ReactionDelete) error {
+	return s.reactionRepository.Delete(&domain.Reaction{
+		UserID:     req.UserID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Type:       req.Type,
+	})
+}
+
+// visible returns gorm.ErrRecordNotFound when the target cannot receive
+// reactions from userID: comments must be readable by the user and journals
+// must be public.
+func (s *reactionService) visible(target domain.ReactionTarget, id uint, userID uint) error {
+	switch target {
+	case domain.ForumTarget:
+		_, err := s.forumRepository.FindByID(id)
+		return err
+	case domain.CommentTarget:
+		comment, err := s.commentRepository.FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		if comment.Deleted || (comment.Visibility != domain.PublicComment && comment.UserID != userID) {
+			return gorm.ErrRecordNotFound
+		}
+	case domain.JournalTarget:
+		journal, err := s.journalRepository.FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		if journal.Visibility != domain.PublicJournal {
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	return nil
+}
+
+// reactionSummaries aggregates the reactions of every target in ids, with
+// the ones left by userID. Targets without reactions get an empty summary.
+func reactionSummaries(reactionRepository repository.ReactionRepository, target domain.ReactionTarget, ids []uint, userID uint) (map[uint]*web.ReactionSummary, error) {
+	summaries := make(map[uint]*web.ReactionSummary, len(ids))
+	for _, id := range ids {
+		summaries[id] = &web.ReactionSummary{
+			Counts: map[domain.ReactionType]int64{},
+			Mine:   []domain.ReactionType{},
+		}
+	}
+
+	counts, err := reactionRepository.CountByTargets(target, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, count := range counts {
+		if summary, ok := summaries[count.TargetID]; ok {
+			summary.Counts[count.Type] = count.Total
+		}
+	}
+
+	mine, err := reactionRepository.FindByUser(userID, target, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, reaction := range mine {
+		if summary, ok := summaries[reaction.TargetID]; ok {
+			summary.Mine = append(summary.Mine, reaction.Type)
+		}
+	}
+
+	return summaries, nil
+}
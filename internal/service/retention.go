@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/aternity/zense/internal/repository"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type RetentionService interface {
+	Run(ctx context.Context)
+}
+
+type RetentionConfig struct {
+	// Retention is how long deleted forums, comments and journals can be
+	// restored before they are purged.
+	Retention time.Duration
+	// WorkerInterval is how often the worker purges them.
+	WorkerInterval time.Duration
+}
+
+type retentionService struct {
+	forumRepository   repository.ForumRepository
+	commentRepository repository.CommentRepository
+	journalRepository repository.JournalRepository
+	config            RetentionConfig
+}
+
+func NewRetentionService(forumRepository repository.ForumRepository, commentRepository repository.CommentRepository, journalRepository repository.JournalRepository, config RetentionConfig) RetentionService {
+	return &retentionService{
+		forumRepository:   forumRepository,
+		commentRepository: commentRepository,
+		journalRepository: journalRepository,
+		config:            config,
+	}
+}
+
+// Run purges the content deleted longer than the retention period ago, until
+// ctx is done.
+func (s *retentionService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.WorkerInterval)
+	defer ticker.Stop()
+
+	for {
+		s.purge()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *retentionService) purge() {
+	before := time.Now().Add(-s.config.Retention)
+
+	purges := []struct {
+		name  string
+		purge func(before time.Time) (int64, error)
+	}{
+		// Forums go first, they take the comments deleted with them along.
+		{"forums", s.forumRepository.Purge},
+		{"comments", s.commentRepository.Purge},
+		{"journals", s.journalRepository.Purge},
+	}
+
+	for _, p := range purges {
+		purged, err := p.purge(before)
+		if err != nil {
+			logrus.WithError(err).Errorf("failed to purge deleted %s", p.name)
+			continue
+		}
+
+		if purged > 0 {
+			logrus.WithField("count", purged).Infof("purged deleted %s", p.name)
+		}
+	}
+}
+
+// deletedAt returns when a soft deleted row was deleted, nil when it was not.
+func deletedAt(deletedAt gorm.DeletedAt) *time.Time {
+	if !deletedAt.Valid {
+		return nil
+	}
+	return &deletedAt.Time
+}
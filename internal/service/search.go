@@ -0,0 +1,50 @@
+package service
+
+import (
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/repository"
+)
+
+type SearchService interface {
+	Search(req web.Search) (*web.PageResponse[web.SearchResponse], error)
+}
+
+type searchService struct {
+	searchRepository repository.SearchRepository
+}
+
+func NewSearchService(searchRepository repository.SearchRepository) SearchService {
+	return &searchService{
+		searchRepository: searchRepository,
+	}
+}
+
+func (s *searchService) Search(req web.Search) (*web.PageResponse[web.SearchResponse], error) {
+	filter := repository.SearchFilter{
+		Query:    req.Query,
+		Language: req.Language,
+		Type:     req.Type,
+		TopicID:  req.TopicID,
+	}
+
+	query := web.PageQuery{
+		Page:  req.Page,
+		Limit: req.Limit,
+	}
+
+	results, err := s.searchRepository.Search(filter, pagination(query, ""))
+	if err != nil {
+		return nil, err
+	}
+
+	return pageResponse(results, query, func(result repository.SearchResult) web.SearchResponse {
+		return web.SearchResponse{
+			Type:      result.Type,
+			ID:        result.ID,
+			Title:     result.Title,
+			Snippet:   result.Snippet,
+			Rank:      result.Rank,
+			CreatedAt: &result.CreatedAt,
+		}
+	}), nil
+}
@@ -0,0 +1,112 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+type ventSession struct {
+	mu       sync.Mutex
+	summary  string
+	history  []string
+	lastSeen time.Time
+}
+
+type ventSessionStore struct {
+	mu          sync.Mutex
+	sessions    map[uint]*ventSession
+	ttl         time.Duration
+	maxMessages int
+	lastSweep   time.Time
+}
+
+func newVentSessionStore(ttl time.Duration, maxMessages int) *ventSessionStore {
+	return &ventSessionStore{
+		sessions:    map[uint]*ventSession{},
+		ttl:         ttl,
+		maxMessages: maxMessages,
+		lastSweep:   time.Now(),
+	}
+}
+
+// get returns the session of the given user, starting a fresh one when none
+// exists or the previous one has been idle for longer than the ttl.
+func (s *ventSessionStore) get(userID uint) *ventSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) > s.ttl {
+		s.sweep(now)
+	}
+
+	session, ok := s.sessions[userID]
+	if !ok || s.expired(session, now) {
+		session = &ventSession{}
+		s.sessions[userID] = session
+	}
+
+	session.mu.Lock()
+	session.lastSeen = now
+	session.mu.Unlock()
+
+	return session
+}
+
+func (s *ventSessionStore) delete(userID uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, userID)
+}
+
+func (s *ventSessionStore) sweep(now time.Time) {
+	for userID, session := range s.sessions {
+		if s.expired(session, now) {
+			delete(s.sessions, userID)
+		}
+	}
+	s.lastSweep = now
+}
+
+func (s *ventSessionStore) expired(session *ventSession, now time.Time) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+
+	return now.Sub(session.lastSeen) > s.ttl
+}
+
+func (s *ventSession) snapshot() (string, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history := make([]string, len(s.history))
+	copy(history, s.history)
+
+	return s.summary, history
+}
+
+// compact replaces the n oldest messages with summary.
+func (s *ventSession) compact(n int, summary string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n > len(s.history) {
+		n = len(s.history)
+	}
+
+	s.history = append([]string(nil), s.history[n:]...)
+	s.summary = summary
+}
+
+// append adds messages to the history and drops the oldest ones once the
+// history grows past max.
+func (s *ventSession) append(max int, messages ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.history = append(s.history, messages...)
+	if max > 0 && len(s.history) > max {
+		s.history = append([]string(nil), s.history[len(s.history)-max:]...)
+	}
+}
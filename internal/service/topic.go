@@ -8,7 +8,7 @@ import (
 
 type TopicService interface {
 	Create(req web.TopicCreate) (*web.TopicResponse, error)
-	FindAll() ([]web.TopicResponse, error)
+	FindAll(req web.TopicFindAll) (*web.PageResponse[web.TopicResponse], error)
 	FindByID(req web.TopicFindByID) (*web.TopicResponse, error)
 	Update(req web.TopicUpdate) (*web.TopicResponse, error)
 	Delete(req web.TopicDelete) error
@@ -26,8 +26,8 @@ func NewTopicService(topicRepository repository.TopicRepository) TopicService {
 
 func (s *topicService) Create(req web.TopicCreate) (*web.TopicResponse, error) {
 	topic := &domain.Topic{
-		Name: req.Name,
-    Description: req.Description,
+		Name:        req.Name,
+		Description: req.Description,
 	}
 
 	topic, err := s.topicRepository.Create(topic)
@@ -36,34 +36,36 @@ func (s *topicService) Create(req web.TopicCreate) (*web.TopicResponse, error) {
 	}
 
 	response := &web.TopicResponse{
-		ID:   topic.ID,
-		Name: topic.Name,
-    Description: topic.Description,
-    CreatedAt: &topic.CreatedAt,
+		ID:          topic.ID,
+		Name:        topic.Name,
+		Description: topic.Description,
+		CreatedAt:   &topic.CreatedAt,
 	}
 
 	return response, nil
 }
 
-func (s *topicService) FindAll() ([]web.TopicResponse, error) {
-	topics, err := s.topicRepository.FindAll()
+func (s *topicService) FindAll(req web.TopicFindAll) (*web.PageResponse[web.TopicResponse], error) {
+	filter := repository.TopicFilter{
+		Name: req.Name,
+	}
+
+	topics, err := s.topicRepository.FindAll(filter, pagination(req.PageQuery, req.Sort))
 	if err != nil {
-		return nil, err
+		return nil, pageError(err)
 	}
 
-	var responses []web.TopicResponse
-	for _, topic := range topics {
-		response := web.TopicResponse{
-			ID:   topic.ID,
-			Name: topic.Name,
-      Description: topic.Description,
-      CreatedAt: &topic.CreatedAt,
-      UpdatedAt: &topic.UpdatedAt,
+	response := pageResponse(topics, req.PageQuery, func(topic domain.Topic) web.TopicResponse {
+		return web.TopicResponse{
+			ID:          topic.ID,
+			Name:        topic.Name,
+			Description: topic.Description,
+			CreatedAt:   &topic.CreatedAt,
+			UpdatedAt:   &topic.UpdatedAt,
 		}
-		responses = append(responses, response)
-	}
+	})
 
-	return responses, nil
+	return response, nil
 }
 
 func (s *topicService) FindByID(req web.TopicFindByID) (*web.TopicResponse, error) {
@@ -73,11 +75,11 @@ func (s *topicService) FindByID(req web.TopicFindByID) (*web.TopicResponse, erro
 	}
 
 	response := &web.TopicResponse{
-		ID:   topic.ID,
-		Name: topic.Name,
-    Description: topic.Description,
-    CreatedAt: &topic.CreatedAt,
-    UpdatedAt: &topic.UpdatedAt,
+		ID:          topic.ID,
+		Name:        topic.Name,
+		Description: topic.Description,
+		CreatedAt:   &topic.CreatedAt,
+		UpdatedAt:   &topic.UpdatedAt,
 	}
 
 	return response, nil
@@ -89,11 +91,11 @@ func (s *topicService) Update(req web.TopicUpdate) (*web.TopicResponse, error) {
 		return nil, err
 	}
 
-  topic = &domain.Topic{
-    ID: req.ID,
-    Name: req.Name,
-    Description: req.Description,
-  }
+	topic = &domain.Topic{
+		ID:          req.ID,
+		Name:        req.Name,
+		Description: req.Description,
+	}
 
 	topic, err = s.topicRepository.Update(topic)
 	if err != nil {
@@ -101,10 +103,10 @@ func (s *topicService) Update(req web.TopicUpdate) (*web.TopicResponse, error) {
 	}
 
 	response := &web.TopicResponse{
-		ID:   topic.ID,
-		Name: topic.Name,
-    Description: topic.Description,
-    UpdatedAt: &topic.UpdatedAt,
+		ID:          topic.ID,
+		Name:        topic.Name,
+		Description: topic.Description,
+		UpdatedAt:   &topic.UpdatedAt,
 	}
 
 	return response, nil
@@ -122,4 +124,3 @@ func (s *topicService) Delete(req web.TopicDelete) error {
 
 	return nil
 }
-
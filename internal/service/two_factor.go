@@ -0,0 +1,232 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/repository"
+	"github.com/aternity/zense/internal/util"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+type TwoFactorService interface {
+	Setup(req web.TwoFactorSetup) (*web.TwoFactorSetupResponse, error)
+	Enable(req web.TwoFactorEnable) (*web.TwoFactorRecoveryCodesResponse, error)
+	Disable(req web.TwoFactorDisable) error
+	RegenerateRecoveryCodes(req web.TwoFactorRecoveryCodes) (*web.TwoFactorRecoveryCodesResponse, error)
+}
+
+type TwoFactorConfig struct {
+	// Issuer names the account in authenticator apps.
+	Issuer string
+}
+
+// recoveryCodeCount is how many recovery codes a user gets at a time.
+const recoveryCodeCount = 10
+
+type twoFactorService struct {
+	userRepository      repository.UserRepository
+	twoFactorRepository repository.TwoFactorRepository
+	config              TwoFactorConfig
+}
+
+func NewTwoFactorService(userRepository repository.UserRepository, twoFactorRepository repository.TwoFactorRepository, config TwoFactorConfig) TwoFactorService {
+	return &twoFactorService{
+		userRepository:      userRepository,
+		twoFactorRepository: twoFactorRepository,
+		config:              config,
+	}
+}
+
+// Setup creates a pending secret for the user to add to their authenticator.
+// Two-factor authentication is only enabled once they confirm a code of it.
+func (s *twoFactorService) Setup(req web.TwoFactorSetup) (*web.TwoFactorSetupResponse, error) {
+	user, err := s.userRepository.FindByID(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := util.NewTOTPSecret()
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to generate secret")
+	}
+
+	if _, err := s.twoFactorRepository.Save(&domain.TwoFactor{
+		UserID: user.ID,
+		Secret: secret,
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "two-factor authentication is already enabled")
+		}
+		return nil, err
+	}
+
+	response := &web.TwoFactorSetupResponse{
+		Secret: secret,
+		URI:    util.TOTPURI(s.config.Issuer, user.Email, secret),
+	}
+
+	return response, nil
+}
+
+// Enable turns on the pending secret with a code of it and returns the first
+// recovery codes.
+func (s *twoFactorService) Enable(req web.TwoFactorEnable) (*web.TwoFactorRecoveryCodesResponse, error) {
+	twoFactor, err := s.twoFactorRepository.FindByUser(req.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "set up two-factor authentication first")
+		}
+		return nil, err
+	}
+
+	if twoFactor.EnabledAt != nil {
+		return nil, echo.NewHTTPError(http.StatusConflict, "two-factor authentication is already enabled")
+	}
+
+	if err := s.check(twoFactor, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, hashed, err := recoveryCodes(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepository.Enable(req.UserID, hashed); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, echo.NewHTTPError(http.StatusConflict, "two-factor authentication is already enabled")
+		}
+		return nil, err
+	}
+
+	return &web.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable turns two-factor authentication off. It takes the password and a
+// code, so a stolen session alone cannot turn it off.
+func (s *twoFactorService) Disable(req web.TwoFactorDisable) error {
+	user, err := s.userRepository.FindByID(req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid password")
+	}
+
+	twoFactor, err := s.enabled(req.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.check(twoFactor, req.Code); err != nil {
+		return err
+	}
+
+	return s.twoFactorRepository.Delete(req.UserID)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user, the
+// previous ones stop working.
+func (s *twoFactorService) RegenerateRecoveryCodes(req web.TwoFactorRecoveryCodes) (*web.TwoFactorRecoveryCodesResponse, error) {
+	twoFactor, err := s.enabled(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.check(twoFactor, req.Code); err != nil {
+		return nil, err
+	}
+
+	codes, hashed, err := recoveryCodes(req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.twoFactorRepository.ReplaceRecoveryCodes(req.UserID, hashed); err != nil {
+		return nil, err
+	}
+
+	return &web.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *twoFactorService) enabled(userID uint) (*domain.TwoFactor, error) {
+	twoFactor, err := s.twoFactorRepository.FindByUser(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if twoFactor == nil || twoFactor.EnabledAt == nil {
+		return nil, echo.NewHTTPError(http.StatusConflict, "two-factor authentication is not enabled")
+	}
+
+	return twoFactor, nil
+}
+
+func (s *twoFactorService) check(twoFactor *domain.TwoFactor, code string) error {
+	ok, err := verifyTwoFactor(s.twoFactorRepository, twoFactor, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "invalid code")
+	}
+	return nil
+}
+
+// verifyTwoFactor checks a code of the authenticator, or an unused recovery
+// code once two-factor authentication is enabled. Either is used up.
+func verifyTwoFactor(twoFactorRepository repository.TwoFactorRepository, twoFactor *domain.TwoFactor, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+
+	if step, ok := util.ValidateTOTP(twoFactor.Secret, code, time.Now()); ok {
+		if err := twoFactorRepository.UseStep(twoFactor.UserID, step); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	}
+
+	if twoFactor.EnabledAt == nil {
+		return false, nil
+	}
+
+	if err := twoFactorRepository.UseRecoveryCode(twoFactor.UserID, util.HashToken(util.NormalizeRecoveryCode(code))); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// recoveryCodes returns new recovery codes to show the user and their hashes
+// to store.
+func recoveryCodes(userID uint) ([]string, []domain.RecoveryCode, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashed := make([]domain.RecoveryCode, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		code, err := util.NewRecoveryCode()
+		if err != nil {
+			return nil, nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to generate recovery codes")
+		}
+
+		codes = append(codes, code)
+		hashed = append(hashed, domain.RecoveryCode{
+			UserID: userID,
+			Hash:   util.HashToken(util.NormalizeRecoveryCode(code)),
+		})
+	}
+
+	return codes, hashed, nil
+}
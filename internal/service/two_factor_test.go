@@ -0,0 +1,94 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aternity/zense/internal/auth"
+	"github.com/aternity/zense/internal/entity/domain"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/util"
+)
+
+// enableTwoFactor sets up and enables two-factor authentication of user 1 and
+// returns the secret, the step of the code it was enabled with and the
+// recovery codes.
+func enableTwoFactor(t *testing.T, twoFactors *fakeTwoFactors) (string, int64, []string) {
+	t.Helper()
+	service := NewTwoFactorService(newFakeUsers(domain.User{ID: 1, Email: "user@example.com"}), twoFactors, TwoFactorConfig{Issuer: "Zense"})
+	principal := auth.Principal{UserID: 1}
+
+	setup, err := service.Setup(web.TwoFactorSetup{Principal: principal})
+	if err != nil {
+		t.Fatalf("Setup() = %v", err)
+	}
+
+	step := time.Now().Unix() / 30
+	code, err := util.TOTPCode(setup.Secret, step)
+	if err != nil {
+		t.Fatalf("TOTPCode() = %v", err)
+	}
+
+	enabled, err := service.Enable(web.TwoFactorEnable{Principal: principal, Code: code})
+	if err != nil {
+		t.Fatalf("Enable() = %v", err)
+	}
+	return setup.Secret, step, enabled.RecoveryCodes
+}
+
+func TestVerifyTwoFactorRefusesUsedStep(t *testing.T) {
+	twoFactors := newFakeTwoFactors()
+	secret, step, _ := enableTwoFactor(t, twoFactors)
+
+	verify := func(step int64) bool {
+		t.Helper()
+		twoFactor, err := twoFactors.FindByUser(1)
+		if err != nil {
+			t.Fatalf("FindByUser() = %v", err)
+		}
+		code, err := util.TOTPCode(secret, step)
+		if err != nil {
+			t.Fatalf("TOTPCode() = %v", err)
+		}
+		ok, err := verifyTwoFactor(twoFactors, twoFactor, code)
+		if err != nil {
+			t.Fatalf("verifyTwoFactor() = %v", err)
+		}
+		return ok
+	}
+
+	if verify(step) {
+		t.Fatal("the code Enable used was accepted again")
+	}
+	if !verify(step + 1) {
+		t.Fatal("the code of the next step was refused")
+	}
+	if verify(step + 1) {
+		t.Fatal("the code of the next step was accepted twice")
+	}
+	if verify(step) {
+		t.Fatal("a code older than the last one used was accepted")
+	}
+}
+
+func TestVerifyTwoFactorUsesRecoveryCodesOnce(t *testing.T) {
+	twoFactors := newFakeTwoFactors()
+	_, _, codes := enableTwoFactor(t, twoFactors)
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("Enable() returned %d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+
+	twoFactor, err := twoFactors.FindByUser(1)
+	if err != nil {
+		t.Fatalf("FindByUser() = %v", err)
+	}
+
+	for _, code := range codes {
+		if ok, err := verifyTwoFactor(twoFactors, twoFactor, code); err != nil || !ok {
+			t.Fatalf("verifyTwoFactor(%s) = %t, %v, want true", code, ok, err)
+		}
+		if ok, err := verifyTwoFactor(twoFactors, twoFactor, code); err != nil || ok {
+			t.Fatalf("verifyTwoFactor(%s) a second time = %t, %v, want false", code, ok, err)
+		}
+	}
+}
@@ -1,122 +1,89 @@
 package service
 
 import (
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/aternity/zense/internal/entity/domain"
 	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/mail"
 	"github.com/aternity/zense/internal/repository"
-	"github.com/aternity/zense/internal/util"
 	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type UserService interface {
-	Login(req web.UserLogin) (*web.UserAuth, error)
-	Register(req web.UserRegister) (*web.UserResponse, error)
 	FindMe(req web.UserFindMe) (*web.UserResponse, error)
-	FindAll() ([]web.UserResponse, error)
+	FindAll(req web.UserFindAll) (*web.PageResponse[web.UserResponse], error)
 	FindByID(req web.UserFindByID) (*web.UserResponse, error)
 	Update(req web.UserUpdate) (*web.UserResponse, error)
-	Delete(req web.UserDelete) error
+	UpdateRole(req web.UserUpdateRole) (*web.UserResponse, error)
+	BootstrapAdmin(req web.UserBootstrapAdmin) error
 }
 
 type userService struct {
-	userRepository repository.UserRepository
-	jwt            *util.JWT
+	userRepository      repository.UserRepository
+	sessionRepository   repository.SessionRepository
+	userTokenRepository repository.UserTokenRepository
+	twoFactorRepository repository.TwoFactorRepository
+	mailer              mail.Mailer
+	config              AuthConfig
 }
 
-func NewUserService(userRepository repository.UserRepository, jwt *util.JWT) UserService {
+func NewUserService(userRepository repository.UserRepository, sessionRepository repository.SessionRepository, userTokenRepository repository.UserTokenRepository, twoFactorRepository repository.TwoFactorRepository, mailer mail.Mailer, config AuthConfig) UserService {
 	return &userService{
-		userRepository: userRepository,
-		jwt:            jwt,
+		userRepository:      userRepository,
+		sessionRepository:   sessionRepository,
+		userTokenRepository: userTokenRepository,
+		twoFactorRepository: twoFactorRepository,
+		mailer:              mailer,
+		config:              config,
 	}
 }
 
-func (s *userService) Login(req web.UserLogin) (*web.UserAuth, error) {
-	user, err := s.userRepository.FindByEmail(req.Email)
-	if err != nil {
-		return nil, echo.NewHTTPError(http.StatusUnauthorized, "user not found")
-	}
-
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, echo.NewHTTPError(http.StatusUnauthorized, "invalid password")
-	}
-
-	token, err := s.jwt.GenerateToken(user.ID)
-	if err != nil {
-		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to generate token")
-	}
-
-	response := &web.UserAuth{
-		ID:    user.ID,
-		Name:  user.Name,
-		Token: token,
-	}
-
-	return response, nil
-}
-
-func (s *userService) Register(req web.UserRegister) (*web.UserResponse, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	if err != nil {
-		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to hash password")
-	}
-
-	user := &domain.User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: string(hashedPassword),
-	}
-
-	user, err = s.userRepository.Create(user)
-	if err != nil {
-		return nil, echo.NewHTTPError(http.StatusInternalServerError, "failed to create user")
-	}
-
-	response := &web.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		CreatedAt: &user.CreatedAt,
-	}
-
-	return response, nil
-}
-
 func (s *userService) FindMe(req web.UserFindMe) (*web.UserResponse, error) {
-	user, err := s.userRepository.FindByID(req.ID)
+	user, err := s.userRepository.FindByID(req.UserID)
 	if err != nil {
 		return nil, err
 	}
 
 	response := &web.UserResponse{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		CreatedAt: &user.CreatedAt,
-		UpdatedAt: &user.UpdatedAt,
+		ID:         user.ID,
+		Name:       user.Name,
+		Email:      user.Email,
+		Role:       user.Role,
+		VerifiedAt: user.VerifiedAt,
+		CreatedAt:  &user.CreatedAt,
+		UpdatedAt:  &user.UpdatedAt,
 	}
 
 	return response, nil
 }
 
-func (s *userService) FindAll() ([]web.UserResponse, error) {
-	users, err := s.userRepository.FindAll()
+func (s *userService) FindAll(req web.UserFindAll) (*web.PageResponse[web.UserResponse], error) {
+	filter := repository.UserFilter{
+		Name: req.Name,
+	}
+
+	users, err := s.userRepository.FindAll(filter, pagination(req.PageQuery, req.Sort))
 	if err != nil {
-		return nil, err
+		return nil, pageError(err)
 	}
 
-	var responses []web.UserResponse
-	for _, user := range users {
-		responses = append(responses, web.UserResponse{
+	response := pageResponse(users, req.PageQuery, func(user domain.User) web.UserResponse {
+		return web.UserResponse{
 			ID:        user.ID,
 			Name:      user.Name,
 			CreatedAt: &user.CreatedAt,
 			UpdatedAt: &user.UpdatedAt,
-		})
-	}
+		}
+	})
 
-	return responses, nil
+	return response, nil
 }
 
 func (s *userService) FindByID(req web.UserFindByID) (*web.UserResponse, error) {
@@ -135,6 +102,9 @@ func (s *userService) FindByID(req web.UserFindByID) (*web.UserResponse, error)
 	return response, nil
 }
 
+// Update changes the name, email or password of the user. Changing the email
+// or the password asks for the current password and two-factor code again,
+// a new email has to be verified and a new password ends the other sessions.
 func (s *userService) Update(req web.UserUpdate) (*web.UserResponse, error) {
 	user, err := s.userRepository.FindByID(req.ID)
 	if err != nil {
@@ -145,6 +115,17 @@ func (s *userService) Update(req web.UserUpdate) (*web.UserResponse, error) {
 		return nil, echo.NewHTTPError(http.StatusForbidden, "you do not have permission to update this user")
 	}
 
+	if req.Email != "" && reservedEmail(req.Email) {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "this email address is reserved")
+	}
+
+	changeEmail := req.Email != "" && req.Email != user.Email
+	if changeEmail || req.Password != "" {
+		if err := reauthenticate(s.twoFactorRepository, user, req.CurrentPassword, req.Code, req.AuthenticatedAt, s.config.ReauthWindow); err != nil {
+			return nil, err
+		}
+	}
+
 	if req.Password != "" {
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 		if err != nil {
@@ -153,41 +134,140 @@ func (s *userService) Update(req web.UserUpdate) (*web.UserResponse, error) {
 		req.Password = string(hashedPassword)
 	}
 
-	user = &domain.User{
+	updated := &domain.User{
 		ID:       req.ID,
 		Name:     req.Name,
 		Email:    req.Email,
 		Password: req.Password,
 	}
 
+	if changeEmail {
+		updated, err = s.userRepository.UpdateEmail(updated)
+	} else {
+		updated, err = s.userRepository.Update(updated)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Password != "" {
+		if err := s.sessionRepository.RevokeOthers(user.ID, req.SessionID); err != nil {
+			return nil, err
+		}
+	}
+
+	if changeEmail {
+		user.Email = req.Email
+		if req.Name != "" {
+			user.Name = req.Name
+		}
+		if err := sendVerification(s.userTokenRepository, s.mailer, s.config, user); err != nil {
+			logrus.WithError(err).WithField("user_id", user.ID).Error("failed to send verification email")
+		}
+	}
+
+	response := &web.UserResponse{
+		ID:        updated.ID,
+		Name:      updated.Name,
+		UpdatedAt: &updated.UpdatedAt,
+	}
+
+	return response, nil
+}
+
+// UpdateRole gives a user another role. Changing it ends every session of
+// the user, who logs in again to get tokens with the new role.
+func (s *userService) UpdateRole(req web.UserUpdateRole) (*web.UserResponse, error) {
+	user, err := s.userRepository.FindByID(req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID == req.UserID {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "you cannot change your own role")
+	}
+
+	if user.Role == domain.DeletedUser {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "the deleted user cannot be given a role")
+	}
+
+	changed := user.Role != req.Role
+
+	user = &domain.User{
+		ID:   req.ID,
+		Role: req.Role,
+	}
+
 	user, err = s.userRepository.Update(user)
 	if err != nil {
 		return nil, err
 	}
 
+	// Access tokens carry the role, so the old ones must not outlive it.
+	if changed {
+		if err := s.sessionRepository.RevokeUser(user.ID); err != nil {
+			return nil, err
+		}
+	}
+
 	response := &web.UserResponse{
 		ID:        user.ID,
-		Name:      user.Name,
+		Role:      user.Role,
 		UpdatedAt: &user.UpdatedAt,
 	}
 
 	return response, nil
 }
 
-func (s *userService) Delete(req web.UserDelete) error {
-	user, err := s.userRepository.FindByID(req.ID)
+// reservedEmail tells whether email is the one of the deleted user, which
+// nobody can sign up or change to.
+func reservedEmail(email string) bool {
+	return strings.EqualFold(strings.TrimSpace(email), domain.DeletedUserEmail)
+}
+
+// BootstrapAdmin makes sure the account with the given email is an admin,
+// creating it when it does not exist yet and a password is provided.
+func (s *userService) BootstrapAdmin(req web.UserBootstrapAdmin) error {
+	user, err := s.userRepository.FindByEmail(req.Email)
 	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if req.Password == "" {
+			return errors.New("admin account does not exist and no password was provided to create it")
+		}
+
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		_, err = s.userRepository.Create(&domain.User{
+			Name:       "admin",
+			Email:      req.Email,
+			Password:   string(hashedPassword),
+			Role:       domain.AdminUser,
+			VerifiedAt: &now,
+		})
 		return err
 	}
 
-	if user.ID != req.UserID {
-		return echo.NewHTTPError(http.StatusForbidden, "you do not have permission to delete this user")
+	if user.Role == domain.AdminUser {
+		return nil
 	}
 
-	if err := s.userRepository.Delete(user); err != nil {
-		return err
+	// Anyone could have registered the address first, so only an account
+	// proven to be the operator's is promoted: one whose email was verified
+	// or whose password is ADMIN_PASSWORD.
+	if user.VerifiedAt == nil && (req.Password == "" || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)) != nil) {
+		return errors.New("admin account is not verified, verify its email or set ADMIN_PASSWORD to its password")
 	}
 
-	return nil
+	_, err = s.userRepository.Update(&domain.User{
+		ID:   user.ID,
+		Role: domain.AdminUser,
+	})
+	return err
 }
-
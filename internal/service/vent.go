@@ -2,59 +2,244 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"strings"
+	"time"
 
+	"github.com/aternity/zense/internal/entity/domain"
 	"github.com/aternity/zense/internal/entity/web"
-	"github.com/google/generative-ai-go/genai"
+	"github.com/aternity/zense/internal/llm"
+	"github.com/aternity/zense/internal/prompt"
+	"github.com/aternity/zense/internal/repository"
+	"github.com/aternity/zense/internal/safety"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
 )
 
 type VentService interface {
 	Chat(ctx context.Context, req *web.VentRequest) (*web.VentResponse, error)
-	Clear()
+	Stream(ctx context.Context, req *web.VentRequest, fn func(chunk string) error) (*web.VentResponse, error)
+	Clear(req web.VentClear)
+}
+
+type VentConfig struct {
+	SessionTTL  time.Duration
+	MaxMessages int
+	TokenBudget int
 }
 
 type ventService struct {
-	client              *genai.Client
-	conversationHistory []string
+	llm                   llm.Provider
+	classifier            safety.Classifier
+	prompts               *prompt.Registry
+	safetyEventRepository repository.SafetyEventRepository
+	ventReplyRepository   repository.VentReplyRepository
+	sessions              *ventSessionStore
+	window                *ventContext
 }
 
-func NewVentService(client *genai.Client) VentService {
+func NewVentService(
+	llm llm.Provider,
+	classifier safety.Classifier,
+	prompts *prompt.Registry,
+	safetyEventRepository repository.SafetyEventRepository,
+	ventReplyRepository repository.VentReplyRepository,
+	config VentConfig,
+) VentService {
 	return &ventService{
-		client:              client,
-		conversationHistory: []string{},
+		llm:                   llm,
+		classifier:            classifier,
+		prompts:               prompts,
+		safetyEventRepository: safetyEventRepository,
+		ventReplyRepository:   ventReplyRepository,
+		sessions:              newVentSessionStore(config.SessionTTL, config.MaxMessages),
+		window:                newVentContext(llm, config.TokenBudget),
 	}
 }
 
+// streamWindow is how much text a stream holds back at most when no sentence
+// ends sooner. Held text is screened before it is sent.
+const streamWindow = 200
+
+// errUnsafeStream stops the model once the streamed reply turns high risk.
+var errUnsafeStream = errors.New("vent: unsafe reply")
+
 func (s *ventService) Chat(ctx context.Context, req *web.VentRequest) (*web.VentResponse, error) {
-	s.conversationHistory = append(s.conversationHistory, fmt.Sprintf("User: %s", req.Message))
+	return s.reply(ctx, req, func(ctx context.Context, prompt string, language string) (string, safety.Assessment, error) {
+		aiResponse, err := s.llm.Generate(ctx, prompt)
+		return aiResponse, safety.Assessment{}, err
+	})
+}
+
+// Stream sends the reply to fn a sentence, or streamWindow, at a time. The
+// reply so far is screened before each send, and once it is high risk the
+// model is stopped and nothing more is sent, the response carries the safe
+// message instead.
+func (s *ventService) Stream(ctx context.Context, req *web.VentRequest, fn func(chunk string) error) (*web.VentResponse, error) {
+	return s.reply(ctx, req, func(ctx context.Context, prompt string, language string) (string, safety.Assessment, error) {
+		var (
+			aiResponse strings.Builder
+			sent       int
+			held       safety.Assessment
+		)
+
+		flush := func(end int) error {
+			if end <= sent {
+				return nil
+			}
+
+			assessment, err := s.classifier.Classify(ctx, language, aiResponse.String())
+			if err != nil {
+				return err
+			}
+			if assessment.Level == safety.HighRisk {
+				held = assessment
+				return errUnsafeStream
+			}
+
+			chunk := aiResponse.String()[sent:end]
+			sent = end
+			return fn(chunk)
+		}
 
-	combinedConversation := strings.Join(s.conversationHistory, "\n")
+		err := s.llm.Stream(ctx, prompt, func(chunk string) error {
+			aiResponse.WriteString(chunk)
 
-	prompt := fmt.Sprintf(`
-    Kamu adalah teman yang dipercaya. Tanggapi pesan berikut dengan empati, gunakan Bahasa Indonesia.
-    Berikut adalah percakapan sejauh ini:
-    %s
-    Pesan terbaru adalah: '%s'. 
-    Tolong berikan jawaban yang singkat.
-  `, combinedConversation, req.Message)
+			pending := aiResponse.String()[sent:]
+			if i := strings.LastIndexAny(pending, ".!?\n"); i >= 0 {
+				return flush(sent + i + 1)
+			}
+			if len(pending) >= streamWindow {
+				return flush(aiResponse.Len())
+			}
+			return nil
+		})
+		if err == nil {
+			err = flush(aiResponse.Len())
+		}
+		if errors.Is(err, errUnsafeStream) {
+			err = nil
+		}
 
-	model := s.client.GenerativeModel("gemini-1.5-flash")
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+		return aiResponse.String(), held, err
+	})
+}
+
+func (s *ventService) Clear(req web.VentClear) {
+	s.sessions.delete(req.UserID)
+}
+
+// reply screens the message, asks the model for an answer through generate
+// unless the message is high risk, and screens the answer before it is kept
+// in the session. generate returns what it already screened the answer as
+// while streaming it.
+func (s *ventService) reply(ctx context.Context, req *web.VentRequest, generate func(ctx context.Context, prompt string, language string) (string, safety.Assessment, error)) (*web.VentResponse, error) {
+	persona, language := req.Persona, req.Language
+	if persona == "" {
+		persona = "listener"
+	}
+	if language == "" {
+		language = "id"
+	}
+
+	template, err := s.prompts.Pick(persona, language, req.UserID)
 	if err != nil {
+		if errors.Is(err, prompt.ErrNotFound) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "persona is not available in this language")
+		}
 		return nil, err
 	}
 
-	aiResponse := resp.Candidates[0].Content.Parts[0]
-	s.conversationHistory = append(s.conversationHistory, fmt.Sprintf("AI: %s", aiResponse))
+	session := s.sessions.get(req.UserID)
+	message := fmt.Sprintf("User: %s", req.Message)
+
+	assessment, err := s.screen(ctx, req.UserID, language, safety.InputStage, req.Message)
+	if err != nil {
+		return nil, err
+	}
 
 	response := &web.VentResponse{
-		Message: fmt.Sprintf("%s", aiResponse),
+		Persona: persona,
+	}
+
+	var aiResponse string
+	if assessment.Level == safety.HighRisk {
+		aiResponse = safety.Message(language)
+	} else {
+		input, err := s.window.build(ctx, session, message, func(summary, history string) (string, error) {
+			return template.Render(prompt.Data{
+				Summary: summary,
+				History: history,
+				Message: req.Message,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var streamed safety.Assessment
+		aiResponse, streamed, err = generate(ctx, input, language)
+		if err != nil {
+			return nil, err
+		}
+		response.TemplateVersion = template.Version
+
+		if _, err := s.ventReplyRepository.Create(&domain.VentReply{
+			UserID:          req.UserID,
+			Persona:         persona,
+			Language:        language,
+			TemplateVersion: template.Version,
+		}); err != nil {
+			logrus.WithError(err).Error("failed to record vent reply")
+		}
+
+		output, err := s.screen(ctx, req.UserID, language, safety.OutputStage, aiResponse)
+		if err != nil {
+			return nil, err
+		}
+
+		output = safety.Max(output, streamed)
+		if output.Level == safety.HighRisk {
+			aiResponse = safety.Message(language)
+		}
+		assessment = safety.Max(assessment, output)
+	}
+
+	session.append(s.sessions.maxMessages, message, fmt.Sprintf("AI: %s", aiResponse))
+	response.Message = aiResponse
+
+	if assessment.Flagged() {
+		response.Safety = &web.VentSafety{
+			Level:     assessment.Level,
+			Category:  assessment.Category,
+			Resources: safety.Resources(language),
+		}
 	}
 
 	return response, nil
 }
 
-func (s *ventService) Clear() {
-	s.conversationHistory = []string{}
+func (s *ventService) screen(ctx context.Context, userID uint, language string, stage safety.Stage, text string) (safety.Assessment, error) {
+	assessment, err := s.classifier.Classify(ctx, language, text)
+	if err != nil {
+		return safety.Assessment{}, err
+	}
+
+	if assessment.Flagged() {
+		// A failing audit write must never keep crisis resources from the user.
+		if _, err := s.safetyEventRepository.Create(&domain.SafetyEvent{
+			UserID:   userID,
+			Stage:    string(stage),
+			Level:    string(assessment.Level),
+			Category: string(assessment.Category),
+			Rule:     assessment.Rule,
+			Language: language,
+		}); err != nil {
+			logrus.WithError(err).Error("failed to record safety event")
+		}
+	}
+
+	return assessment, nil
 }
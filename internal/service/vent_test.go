@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aternity/zense/internal/auth"
+	"github.com/aternity/zense/internal/entity/web"
+	"github.com/aternity/zense/internal/llm"
+	"github.com/aternity/zense/internal/prompt"
+	"github.com/aternity/zense/internal/safety"
+)
+
+type testVent struct {
+	service      VentService
+	safetyEvents *fakeSafetyEvents
+	ventReplies  *fakeVentReplies
+}
+
+// newTestVent returns a vent service on the stub provider, the built-in
+// prompts and safety rules.
+func newTestVent(t *testing.T) *testVent {
+	t.Helper()
+	prompts := prompt.NewRegistry()
+	if err := prompts.LoadDefaults(); err != nil {
+		t.Fatalf("LoadDefaults() = %v", err)
+	}
+
+	test := &testVent{
+		safetyEvents: &fakeSafetyEvents{},
+		ventReplies:  &fakeVentReplies{},
+	}
+	test.service = NewVentService(llm.NewStub(), safety.NewRuleClassifier(safety.DefaultRules(), "id"), prompts, test.safetyEvents, test.ventReplies, VentConfig{
+		SessionTTL:  time.Hour,
+		MaxMessages: 20,
+		TokenBudget: 2000,
+	})
+	return test
+}
+
+func ventRequest(message string) *web.VentRequest {
+	return &web.VentRequest{Principal: auth.Principal{UserID: 1}, Message: message, Language: "en"}
+}
+
+func TestVentStreamSendsChatReply(t *testing.T) {
+	ctx := context.Background()
+	req := ventRequest("I had a long day at work and I feel tired.")
+
+	chat, err := newTestVent(t).service.Chat(ctx, req)
+	if err != nil {
+		t.Fatalf("Chat() = %v", err)
+	}
+	if chat.Message == "" || chat.Persona != "listener" || chat.TemplateVersion == "" || chat.Safety != nil {
+		t.Fatalf("Chat() = %+v, want a reply of the listener", chat)
+	}
+
+	vent := newTestVent(t)
+	var chunks []string
+	stream, err := vent.service.Stream(ctx, req, func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream() = %v", err)
+	}
+
+	// The stub answers the same prompt the same way, streamed or not.
+	if stream.Message != chat.Message {
+		t.Errorf("Stream() replied %q, Chat() %q", stream.Message, chat.Message)
+	}
+	if len(chunks) == 0 || strings.Join(chunks, "") != stream.Message {
+		t.Errorf("Stream() sent %q, want the chunks of %q", chunks, stream.Message)
+	}
+	if len(vent.ventReplies.replies) != 1 || len(vent.safetyEvents.events) != 0 {
+		t.Errorf("recorded %d replies and %d safety events, want 1 and 0", len(vent.ventReplies.replies), len(vent.safetyEvents.events))
+	}
+}
+
+func TestVentStreamAnswersHighRiskWithResources(t *testing.T) {
+	vent := newTestVent(t)
+
+	var chunks []string
+	response, err := vent.service.Stream(context.Background(), ventRequest("I want to kill myself."), func(chunk string) error {
+		chunks = append(chunks, chunk)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Stream() = %v", err)
+	}
+
+	if len(chunks) != 0 {
+		t.Errorf("Stream() sent %q from the model for a high risk message", chunks)
+	}
+	if response.Message != safety.Message("en") || response.Safety == nil || response.Safety.Level != safety.HighRisk {
+		t.Fatalf("Stream() = %+v, want the safe message with resources", response)
+	}
+	if len(vent.ventReplies.replies) != 0 || len(vent.safetyEvents.events) != 1 {
+		t.Errorf("recorded %d replies and %d safety events, want 0 and 1", len(vent.ventReplies.replies), len(vent.safetyEvents.events))
+	}
+}
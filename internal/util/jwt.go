@@ -1,59 +1,242 @@
 package util
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// JWT signs access tokens with its first key and verifies them with any of
+// its keys, picked by the kid of the token. Keeping the previous keys after
+// a new one is added first lets tokens signed before the rotation stay valid
+// until they expire.
 type JWT struct {
-	Secret string
+	// Issuer (iss) and Audience (aud) are set on the tokens issued and
+	// required on the tokens accepted.
+	Issuer   string
+	Audience string
+	// TTL is how long an access token is valid.
+	TTL  time.Duration
+	keys []*Key
 }
 
+// Key is a key access tokens are signed or verified with. Public keys only
+// verify, keys of retired private keys can be kept as public keys.
+type Key struct {
+	ID     string
+	Method jwt.SigningMethod
+	sign   crypto.PrivateKey
+	verify crypto.PublicKey
+}
+
+// Claims of an access token. SessionID is the session the token was issued
+// for, and the registered ID (jti) lets the token be revoked on its own.
+// Verified tells whether the user had verified their email when the token was
+// issued, and AuthTime is when they logged in to start the session.
 type Claims struct {
-	UserID uint `json:"user_id"`
+	UserID    uint             `json:"user_id"`
+	Role      string           `json:"role"`
+	Verified  bool             `json:"verified"`
+	SessionID string           `json:"sid,omitempty"`
+	AuthTime  *jwt.NumericDate `json:"auth_time"`
 	jwt.RegisteredClaims
 }
 
-func NewJWT(secret string) *JWT {
+// NewJWT returns a JWT signing with the first of keys, which must be a
+// private key.
+func NewJWT(issuer string, audience string, ttl time.Duration, keys ...*Key) (*JWT, error) {
+	if issuer == "" || audience == "" {
+		return nil, errors.New("jwt: issuer and audience are required")
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("jwt: no keys")
+	}
+
+	if keys[0].sign == nil {
+		return nil, errors.New("jwt: the first key must be a private key to sign with")
+	}
+
+	ids := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if ids[key.ID] {
+			return nil, fmt.Errorf("jwt: duplicate key %q", key.ID)
+		}
+		ids[key.ID] = true
+	}
+
 	return &JWT{
-		Secret: secret,
+		Issuer:   issuer,
+		Audience: audience,
+		TTL:      ttl,
+		keys:     keys,
+	}, nil
+}
+
+// NewHMACKey returns an HS256 key of secret, for deployments without key
+// files. It is never published in the key set.
+func NewHMACKey(secret string) *Key {
+	return &Key{
+		ID:     "hs256",
+		Method: jwt.SigningMethodHS256,
+		sign:   []byte(secret),
+		verify: []byte(secret),
 	}
 }
 
-func (j *JWT) GenerateToken(userID uint) (string, error) {
-	exp := time.Now().Add(time.Hour * 72)
-	claims := Claims{
-		UserID: userID,
+// ParseKey reads an RSA (RS256) or Ed25519 (EdDSA) key in PEM, private in
+// PKCS #1 or PKCS #8 or public in PKIX. Its ID is the RFC 7638 thumbprint of
+// the public key, so it stays the same wherever the key is loaded.
+func ParseKey(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("jwt: no PEM block found")
+	}
+
+	var (
+		private crypto.PrivateKey
+		public  crypto.PublicKey
+		err     error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		private, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		private, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		public, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("jwt: unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jwt: %w", err)
+	}
+
+	switch k := private.(type) {
+	case *rsa.PrivateKey:
+		public = &k.PublicKey
+	case ed25519.PrivateKey:
+		public = k.Public()
+	case nil:
+	default:
+		return nil, fmt.Errorf("jwt: unsupported private key %T", private)
+	}
+
+	var method jwt.SigningMethod
+	switch public.(type) {
+	case *rsa.PublicKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("jwt: unsupported public key %T", public)
+	}
+
+	jwk, err := newJWK(public)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Key{
+		ID:     jwk.thumbprint(),
+		Method: method,
+		sign:   private,
+		verify: public,
+	}, nil
+}
+
+// GenerateToken issues an access token of the session the user logged in to
+// at authTime and returns it with its claims.
+func (j *JWT) GenerateToken(userID uint, role string, verified bool, sessionID string, authTime time.Time) (string, *Claims, error) {
+	id, err := RandomToken(16)
+	if err != nil {
+		return "", nil, err
+	}
+
+	now := time.Now()
+	claims := &Claims{
+		UserID:    userID,
+		Role:      role,
+		Verified:  verified,
+		SessionID: sessionID,
+		AuthTime:  jwt.NewNumericDate(authTime),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(exp),
+			ID:        id,
+			Issuer:    j.Issuer,
+			Audience:  jwt.ClaimStrings{j.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(j.TTL)),
 		},
 	}
 
-	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(j.Secret))
+	key := j.keys[0]
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+
+	signed, err := token.SignedString(key.sign)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return signed, claims, nil
 }
 
-func (j *JWT) ValidateToken(token string, secret string) (*Claims, error) {
-	tokenString, err := jwt.ParseWithClaims(token, &Claims{}, func(t *jwt.Token) (interface{}, error) {
-		return []byte(secret), nil
-	})
+// Keyfunc returns the key of the kid of a token, refusing tokens whose
+// algorithm is not the one of the key.
+func (j *JWT) Keyfunc(t *jwt.Token) (interface{}, error) {
+	kid, _ := t.Header["kid"].(string)
 
-	if err != nil {
-		return nil, err
+	for _, key := range j.keys {
+		if key.ID != kid {
+			continue
+		}
+
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("jwt: key %q does not use %s", kid, t.Method.Alg())
+		}
+		return key.verify, nil
 	}
 
-	if !tokenString.Valid {
-		return nil, errors.New("jwt token not valid")
+	return nil, fmt.Errorf("jwt: unknown key %q", kid)
+}
+
+// Methods returns the algorithms of the keys, the only ones tokens are
+// accepted with.
+func (j *JWT) Methods() []string {
+	var methods []string
+	seen := make(map[string]bool)
+	for _, key := range j.keys {
+		if alg := key.Method.Alg(); !seen[alg] {
+			seen[alg] = true
+			methods = append(methods, alg)
+		}
 	}
+	return methods
+}
 
-	claims, ok := tokenString.Claims.(*Claims)
-	if !ok {
-		return nil, errors.New("invalid token claims")
+// ValidateToken parses an access token, refusing it unless it is signed by
+// one of the keys, issued by Issuer for Audience and not expired.
+func (j *JWT) ValidateToken(token string) (*Claims, error) {
+	parsed, err := jwt.ParseWithClaims(token, &Claims{}, j.Keyfunc,
+		jwt.WithValidMethods(j.Methods()),
+		jwt.WithIssuer(j.Issuer),
+		jwt.WithAudience(j.Audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
 	}
 
-	if claims.ExpiresAt.Unix() < time.Now().Unix() {
-		return nil, errors.New("token has expired")
+	claims, ok := parsed.Claims.(*Claims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
 	}
 
 	return claims, nil
@@ -0,0 +1,55 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+var (
+	pseudonymAdjectives = []string{
+		"Brave", "Calm", "Cheerful", "Curious", "Gentle", "Golden", "Hopeful", "Humble",
+		"Kind", "Lively", "Loyal", "Mellow", "Misty", "Patient", "Peaceful", "Quiet",
+		"Radiant", "Serene", "Silent", "Sleepy", "Soft", "Steady", "Sunny", "Swift",
+		"Tender", "Thoughtful", "Tranquil", "Warm", "Wandering", "Wise", "Young", "Zesty",
+	}
+
+	pseudonymAnimals = []string{
+		"Otter", "Owl", "Panda", "Fox", "Deer", "Dolphin", "Hedgehog", "Koala",
+		"Penguin", "Rabbit", "Sparrow", "Turtle", "Whale", "Wolf", "Bear", "Cat",
+		"Crane", "Falcon", "Finch", "Gecko", "Heron", "Lynx", "Moth", "Orca",
+		"Robin", "Seal", "Swan", "Tiger", "Badger", "Beaver", "Bison", "Eagle",
+		"Elephant", "Firefly", "Giraffe", "Hummingbird", "Lemur", "Meerkat", "Octopus", "Pelican",
+		"Raccoon", "Salamander", "Sloth", "Squirrel", "Starling", "Tapir", "Walrus", "Wren",
+	}
+)
+
+// Pseudonyms names the authors of anonymous posts. A name is stable for an
+// author within a thread and unrelated across threads, and it is keyed by a
+// secret so it cannot be traced back by hashing every user ID.
+type Pseudonyms struct {
+	Secret string
+}
+
+func NewPseudonyms(secret string) *Pseudonyms {
+	return &Pseudonyms{
+		Secret: secret,
+	}
+}
+
+// Name returns the pseudonym of userID in the thread of forumID, such as
+// "Gentle Owl".
+func (p *Pseudonyms) Name(forumID uint, userID uint) string {
+	mac := hmac.New(sha256.New, []byte(p.Secret))
+
+	var ids [16]byte
+	binary.BigEndian.PutUint64(ids[:8], uint64(forumID))
+	binary.BigEndian.PutUint64(ids[8:], uint64(userID))
+	mac.Write(ids[:])
+
+	sum := mac.Sum(nil)
+	adjective := pseudonymAdjectives[binary.BigEndian.Uint32(sum[0:4])%uint32(len(pseudonymAdjectives))]
+	animal := pseudonymAnimals[binary.BigEndian.Uint32(sum[4:8])%uint32(len(pseudonymAnimals))]
+
+	return adjective + " " + animal
+}
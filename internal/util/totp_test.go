@@ -0,0 +1,71 @@
+package util
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of RFC 6238 Appendix B, "12345678901234567890"
+// in ASCII, in the base32 form secrets are stored in.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// rfc6238Vectors are the SHA-1 test vectors of RFC 6238 Appendix B, cut to
+// the last 6 of their 8 digits.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestTOTPCode(t *testing.T) {
+	for _, vector := range rfc6238Vectors {
+		code, err := TOTPCode(rfc6238Secret, vector.unix/totpPeriod)
+		if err != nil {
+			t.Fatalf("TOTPCode(%d) = %v", vector.unix, err)
+		}
+		if code != vector.code {
+			t.Errorf("TOTPCode at %d = %s, want %s", vector.unix, code, vector.code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	for _, vector := range rfc6238Vectors {
+		step := vector.unix / totpPeriod
+
+		for _, drift := range []int64{-totpPeriod, 0, totpPeriod} {
+			at := time.Unix(vector.unix+drift, 0)
+			got, ok := ValidateTOTP(rfc6238Secret, vector.code, at)
+			if !ok || got != step {
+				t.Errorf("ValidateTOTP(%s) at %d = %d, %t, want %d, true", vector.code, at.Unix(), got, ok, step)
+			}
+		}
+
+		for _, drift := range []int64{-2 * totpPeriod, 2 * totpPeriod} {
+			if vector.unix+drift < 0 {
+				continue
+			}
+
+			at := time.Unix(vector.unix+drift, 0)
+			if _, ok := ValidateTOTP(rfc6238Secret, vector.code, at); ok {
+				t.Errorf("ValidateTOTP(%s) at %d accepted a code two steps away", vector.code, at.Unix())
+			}
+		}
+	}
+}
+
+func TestValidateTOTPRefusesMalformedCodes(t *testing.T) {
+	at := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "94287082", "287o82"} {
+		if _, ok := ValidateTOTP(rfc6238Secret, code, at); ok {
+			t.Errorf("ValidateTOTP(%q) accepted a malformed code", code)
+		}
+	}
+}